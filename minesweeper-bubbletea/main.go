@@ -6,9 +6,11 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/explain"
 	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/game"
 	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/menu"
 	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/messages"
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/replay"
 )
 
 type model struct {
@@ -26,9 +28,17 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case messages.StartNewGameMsg:
-		return m.switchModel(game.NewModel(msg.Minefield))
+		return m.switchModel(game.NewModel(msg.Minefield, msg.Elapsed, msg.Assistance, msg.Clicks))
+	case messages.ExplainMsg:
+		return m.switchModel(explain.NewModel(msg.Minefield, msg.Back))
+	case messages.WatchReplayMsg:
+		return m.switchModel(replay.NewModel(msg.Recording))
 	case messages.ShowMenuMsg:
-		return m.switchModel(menu.NewModel())
+		menuModel := menu.NewModel()
+		if msg.Err != nil {
+			menuModel, _ = menuModel.Update(messages.MenuErrorMsg{Err: msg.Err})
+		}
+		return m.switchModel(menuModel)
 	default:
 		var cmd tea.Cmd
 		m.activeModel, cmd = m.activeModel.Update(msg)
@@ -47,7 +57,7 @@ func (m model) switchModel(newModel tea.Model) (tea.Model, tea.Cmd) {
 
 func main() {
 	m := newModel()
-	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run()
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseAllMotion()).Run()
 	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
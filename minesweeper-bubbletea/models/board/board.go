@@ -0,0 +1,116 @@
+package board
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+var (
+	leftHeaderStyle  = lipgloss.NewStyle().AlignHorizontal(lipgloss.Left).PaddingLeft(1)
+	rightHeaderStyle = lipgloss.NewStyle().AlignHorizontal(lipgloss.Right).PaddingRight(1)
+
+	WinMessageStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#4aa45b")).Foreground(lipgloss.Color("#FFFFFF")).Bold(true).AlignHorizontal(lipgloss.Center)
+	LoseMessageStyle = WinMessageStyle.Background(lipgloss.Color("#ff0000"))
+	HelpStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).MarginTop(1).MarginLeft(1)
+
+	fieldStyle       = lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).Padding(0, 1)
+	cellStyle        = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, false, false, true).Padding(0, 1)
+	topCellStyle     = cellStyle.UnsetBorderTop()
+	leftCellStyle    = cellStyle.UnsetBorderLeft()
+	topLeftCellStyle = leftCellStyle.UnsetBorderTop()
+	// shiftedRowStyle shifts the odd rows of hex grids by half a cell, laying the cells like bricks
+	shiftedRowStyle = lipgloss.NewStyle().PaddingLeft(2)
+
+	boldRedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")).Bold(true)
+
+	cellStatusToString = map[minesweeper.CellStatus]string{
+		minesweeper.Undugged:      "■",
+		minesweeper.NoMinesAround: ColorString("□", "#808080"),
+		minesweeper.MinesAround1:  ColorString("1", "#1e90ff"),
+		minesweeper.MinesAround2:  ColorString("2", "#008000"),
+		minesweeper.MinesAround3:  ColorString("3", "#b22222"),
+		minesweeper.MinesAround4:  ColorString("4", "#4b0082"),
+		minesweeper.MinesAround5:  ColorString("5", "#a52a2a"),
+		minesweeper.MinesAround6:  ColorString("6", "#008080"),
+		minesweeper.MinesAround7:  ColorString("7", "#afeeee"),
+		minesweeper.MinesAround8:  ColorString("8", "#ffa500"),
+		minesweeper.Flagged:       ColorString("?", "#ff6347"),
+		minesweeper.FlaggedWrong:  boldRedStyle.Render("X"),
+		minesweeper.Mine:          ColorString("M", "#ff0000"),
+		minesweeper.Explode:       boldRedStyle.Render("Ж"),
+	}
+)
+
+// CellRenderer renders a single cell, usually by styling CellString with CellStyle
+type CellRenderer func(row, col int, cellStatus minesweeper.CellStatus) string
+
+// RenderField renders the minefield table, cell by cell. The odd rows of hex grids are shifted by half a cell.
+func RenderField(cells [][]minesweeper.CellStatus, topology minesweeper.Topology, renderCell CellRenderer) string {
+	tableView := make([]string, len(cells))
+	for row := range cells {
+		cellsStr := make([]string, len(cells[row]))
+		for col := range cells[row] {
+			cellsStr[col] = renderCell(row, col, cells[row][col])
+		}
+		tableView[row] = lipgloss.JoinHorizontal(lipgloss.Top, cellsStr...)
+		if topology == minesweeper.HexTopology && row%2 == 1 {
+			tableView[row] = shiftedRowStyle.Render(tableView[row])
+		}
+	}
+
+	return fieldStyle.Render(lipgloss.JoinVertical(lipgloss.Left, tableView...))
+}
+
+// CellString returns the colored symbol of a cell status
+func CellString(cellStatus minesweeper.CellStatus) string {
+	return cellStatusToString[cellStatus]
+}
+
+// CellStyle returns the style of a cell, with borders between the cells but not around the table
+func CellStyle(row, col int) lipgloss.Style {
+	if col == 0 {
+		if row == 0 {
+			return topLeftCellStyle
+		}
+		return leftCellStyle
+	}
+	if row == 0 {
+		return topCellStyle
+	}
+	return cellStyle
+}
+
+// TopologyLabel returns the header label of a minefield's topology, flat minefields have none
+func TopologyLabel(topology minesweeper.Topology) string {
+	switch topology {
+	case minesweeper.TorusTopology:
+		return " • Torus"
+	case minesweeper.HexTopology:
+		return " • Hex"
+	}
+	return ""
+}
+
+// RenderHeader renders a line above the field, with left and right aligned parts. The left part gets the space the right part doesn't need.
+func RenderHeader(width int, left, right string) string {
+	right = rightHeaderStyle.Render(right)
+	left = leftHeaderStyle.Width(max(width-lipgloss.Width(right), 0)).Render(left)
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+}
+
+func RealWidthOf(s string) int {
+	width := strings.IndexRune(s, '\n')
+	if width < 0 {
+		return utf8.RuneCountInString(s)
+	}
+
+	return utf8.RuneCountInString(s[:width])
+}
+
+func ColorString(s, color string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(s)
+}
@@ -0,0 +1,48 @@
+package explain
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper/solver"
+)
+
+type model struct {
+	// field is a copy of the explained board, the solver plays on it without touching the game
+	field minesweeper.Minefield
+	// back is the screen to return to, nil returns to the menu
+	back tea.Model
+	err  error
+
+	// deduction is the next forced move and explanation describes it, deduction is nil when there is none
+	deduction   *solver.Deduction
+	explanation string
+	// frontier are the candidates for a guess, set when there is no forced move
+	frontier []minesweeper.Coordinates
+	// steps counts the deductions applied so far, only those can be stepped back
+	steps int
+}
+
+func NewModel(field minesweeper.Minefield, back tea.Model) tea.Model {
+	m := model{back: back}
+	m.field, m.err = minesweeper.RestoreMinefield(field.State())
+	if m.err == nil {
+		m.deduce()
+	}
+	return m
+}
+
+// deduce finds the next forced move of the board
+func (m *model) deduce() {
+	m.deduction = nil
+	m.explanation = ""
+	m.frontier = nil
+
+	deductions := solver.Deduce(m.field)
+	if len(deductions) == 0 {
+		m.frontier = solver.Frontier(m.field)
+		return
+	}
+	m.deduction = &deductions[0]
+	m.explanation = solver.Explain(m.field, deductions[0])
+}
@@ -0,0 +1,93 @@
+package explain
+
+import (
+	"github.com/charmbracelet/bubbles/stopwatch"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/messages"
+	"github.com/HuBeZa/minesweeper/minesweeper/solver"
+)
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case stopwatch.TickMsg, stopwatch.StartStopMsg:
+		// the game clock keeps running while explaining
+		if m.back == nil {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.back, cmd = m.back.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+n":
+			if m.back == nil {
+				return m, messages.ShowMenu
+			}
+			return m.back, nil
+		case "ctrl+c", "ctrl+q":
+			if m.back == nil {
+				return m, tea.Quit
+			}
+			// let the game save itself before quitting
+			return m.back.Update(msg)
+		}
+
+		if m.err != nil {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case " ", "enter", "right", "l":
+			return m.next()
+		case "left", "h", "backspace":
+			return m.previous()
+		case "s":
+			return m.solve()
+		}
+	}
+	return m, nil
+}
+
+// next applies the explained deduction and explains the one after it
+func (m model) next() (tea.Model, tea.Cmd) {
+	if m.deduction == nil {
+		return m, nil
+	}
+	if err := solver.Apply(m.field, *m.deduction); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.steps++
+	m.deduce()
+	return m, nil
+}
+
+// previous undoes the last applied deduction
+func (m model) previous() (tea.Model, tea.Cmd) {
+	if m.steps == 0 {
+		return m, nil
+	}
+	if _, err := m.field.Undo(); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.steps--
+	m.deduce()
+	return m, nil
+}
+
+// solve applies the deductions one by one until there are none left
+func (m model) solve() (tea.Model, tea.Cmd) {
+	for m.deduction != nil && m.err == nil {
+		next, _ := m.next()
+		m = next.(model)
+	}
+	return m, nil
+}
@@ -0,0 +1,105 @@
+package explain
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/board"
+)
+
+var (
+	explanationStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#d7af00")).MarginTop(1).MarginLeft(1)
+	stepStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")).Italic(true).MarginLeft(1)
+	errorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#d70000")).MarginLeft(1)
+
+	safeColor     = lipgloss.Color("#4aa45b")
+	mineColor     = lipgloss.Color("#ff0000")
+	sourceColor   = lipgloss.Color("#d7af00")
+	involvedColor = lipgloss.Color("#3a3a6e")
+)
+
+func (m model) View() string {
+	if m.field == nil {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			errorStyle.Render(fmt.Sprintf("cannot explain this board: %v", m.err)),
+			board.HelpStyle.Render("esc: back • ctrl-q: exit"))
+	}
+
+	field := board.RenderField(m.field.AllCellStatus(), m.field.Topology(), m.renderCell)
+	fieldWidth := board.RealWidthOf(field)
+	leftHeader := fmt.Sprintf("Flags: %v", m.field.FlagsLeft()) + board.TopologyLabel(m.field.Topology())
+	header := board.RenderHeader(fieldWidth, leftHeader, "explain")
+	footer := m.renderFooter(fieldWidth)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, field, footer)
+}
+
+func (m model) renderCell(row, col int, cellStatus minesweeper.CellStatus) string {
+	style := board.CellStyle(row, col)
+	if color, found := m.highlightOf(minesweeper.Coordinates{Row: row, Col: col}); found {
+		style = style.Background(color)
+	}
+	return style.Render(board.CellString(cellStatus))
+}
+
+// highlightOf returns the color of a cell taking part in the explained deduction -
+// the conclusion, the numbers it is based on, and the cells around those numbers.
+// When stuck, the frontier cells are highlighted as the candidates for a guess.
+func (m model) highlightOf(coord minesweeper.Coordinates) (lipgloss.Color, bool) {
+	if m.deduction == nil {
+		if m.field.GameStatus() == minesweeper.GameOn && slices.Contains(m.frontier, coord) {
+			return involvedColor, true
+		}
+		return "", false
+	}
+
+	if m.deduction.Cell == coord {
+		if m.deduction.Mine {
+			return mineColor, true
+		}
+		return safeColor, true
+	}
+	if slices.Contains(m.deduction.Sources, coord) {
+		return sourceColor, true
+	}
+	for _, src := range m.deduction.Sources {
+		if m.field.Topology().AreNeighbors(m.field.Width(), m.field.Height(), src, coord) && m.isHidden(coord) {
+			return involvedColor, true
+		}
+	}
+	return "", false
+}
+
+// isHidden reports whether a cell is undugged or flagged
+func (m model) isHidden(coord minesweeper.Coordinates) bool {
+	status := m.field.CellStatus(coord.Row, coord.Col)
+	return status == minesweeper.Undugged || status == minesweeper.Flagged
+}
+
+func (m model) renderFooter(width int) string {
+	rows := make([]string, 0, 5)
+	switch m.field.GameStatus() {
+	case minesweeper.Won:
+		rows = append(rows, board.WinMessageStyle.Width(width).Render("SOLVED"))
+	case minesweeper.Lost:
+		rows = append(rows, board.LoseMessageStyle.Width(width).Render("LOST - some flags were wrong"))
+	}
+
+	if m.field.GameStatus() == minesweeper.GameOn {
+		explanation := m.explanation
+		if m.deduction == nil {
+			explanation = "no forced move left, the next move is a guess"
+		}
+		rows = append(rows, explanationStyle.Width(width).Render(explanation))
+	}
+	rows = append(rows, stepStyle.Render(fmt.Sprintf("step %v", m.steps)))
+	if m.err != nil {
+		rows = append(rows, errorStyle.Render(m.err.Error()))
+	}
+
+	rows = append(rows, board.HelpStyle.Render("space/→: next • ←: previous • s: solve • esc: back • ctrl-q: exit"))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -2,27 +2,100 @@ package game
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/stopwatch"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	zone "github.com/lrstanley/bubblezone"
 
 	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper/probability"
 )
 
 type model struct {
-	field       minesweeper.Minefield
-	sw          stopwatch.Model
+	field    minesweeper.Minefield
+	recorder minesweeper.Recorder
+	stats    minesweeper.StatsTracker
+	sw       stopwatch.Model
+	// elapsed is the time played before the game was resumed
+	elapsed     time.Duration
 	zone        *zone.Manager
 	started     bool
 	pressedCell *minesweeper.Coordinates
+	// buttonsDown counts the mouse buttons currently pressed, chording is set by a middle click or by both buttons together
+	buttonsDown int
+	chording    bool
+	// keyboard cursor, hidden until the first key press
+	cursor    *minesweeper.Coordinates
+	jumping   bool
+	jumpInput textinput.Model
+	jumpError error
+	// hint is the last hint given, shown until the next move
+	hint *minesweeper.Hint
+	// heatmap tints the undugged cells by their mine probability, hovered is the cell under the mouse
+	heatmap       bool
+	probabilities [][]float64
+	hovered       *minesweeper.Coordinates
+	// games played with hints or heatmap are assisted
+	assistance minesweeper.Assistance
+	// summary is computed once when the game is over, rather than on every render
+	summary *summary
 }
 
-func NewModel(field minesweeper.Minefield) tea.Model {
+// summary is shown below the minefield once the game is over
+type summary struct {
+	// metrics is empty when the minefield can't be analyzed
+	metrics string
+	stats   string
+	code    string
+}
+
+func NewModel(field minesweeper.Minefield, elapsed time.Duration, assistance minesweeper.Assistance, clicks minesweeper.Clicks) tea.Model {
+	stats := minesweeper.NewStatsTracker(field, clicks)
+	recorder := minesweeper.NewRecorder(stats)
 	return model{
-		field: field,
-		sw:    stopwatch.New(),
-		zone:  zone.New(),
+		field:      recorder,
+		recorder:   recorder,
+		stats:      stats,
+		sw:         stopwatch.New(),
+		elapsed:    elapsed,
+		zone:       zone.New(),
+		jumpInput:  newJumpInput(),
+		assistance: assistance,
+	}
+}
+
+// playTime returns the total time played, including the time before resuming
+func (m model) playTime() time.Duration {
+	return m.elapsed + m.sw.Elapsed()
+}
+
+// refreshHeatmap recalculates the mine probabilities after the board changed.
+// Once the game is over the last probabilities are kept, to review the losing move.
+func (m *model) refreshHeatmap() {
+	if !m.heatmap {
+		m.probabilities = nil
+		return
+	}
+	if m.field.GameStatus() != minesweeper.GameOn {
+		return
+	}
+
+	m.probabilities = nil
+	if res, err := probability.Calculate(m.field); err == nil {
+		m.probabilities = res.Cells
+	}
+}
+
+// summarize computes the summary of a finished game
+func (m *model) summarize() {
+	m.summary = &summary{
+		stats: m.stats.Stats(m.playTime()).String(),
+		code:  minesweeper.BoardOf(m.field, m.recorder.Recording().FirstDig()).Code(),
+	}
+	if metrics, err := minesweeper.AnalyzeMinefield(m.field); err == nil {
+		m.summary.metrics = metrics.String()
 	}
 }
 
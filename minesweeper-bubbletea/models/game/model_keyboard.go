@@ -0,0 +1,152 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// cursorMoves maps the movement keys - arrows, vim keys and WASD - to the cursor offset
+var cursorMoves = map[string]minesweeper.Coordinates{
+	"up": {Row: -1}, "k": {Row: -1}, "w": {Row: -1},
+	"down": {Row: 1}, "j": {Row: 1}, "s": {Row: 1},
+	"left": {Col: -1}, "h": {Col: -1}, "a": {Col: -1},
+	"right": {Col: 1}, "l": {Col: 1}, "d": {Col: 1},
+}
+
+func newJumpInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "go to (row col): "
+	input.Placeholder = "0 0"
+	input.CharLimit = 9
+	return input
+}
+
+func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if offset, found := cursorMoves[key]; found {
+		return m.moveCursor(offset.Row, offset.Col)
+	}
+
+	switch key {
+	case "home":
+		return m.setCursorCol(0)
+	case "end":
+		return m.setCursorCol(m.field.Width() - 1)
+	case "pgup":
+		return m.setCursorRow(0)
+	case "pgdown":
+		return m.setCursorRow(m.field.Height() - 1)
+	case "g", ":":
+		m.jumping = true
+		m.jumpError = nil
+		m.jumpInput.Reset()
+		return m, m.jumpInput.Focus()
+	case " ", "enter":
+		return m.applyOnCursor(m.field.Dig)
+	case "f":
+		return m.applyOnCursor(m.field.ToggleFlag)
+	case "c":
+		return m.applyOnCursor(m.field.Chord)
+	case "?":
+		return m.showHint()
+	case "p":
+		return m.toggleHeatmap()
+	case "e":
+		return m.explain()
+	}
+	return m, nil
+}
+
+// moveCursor moves the cursor by an offset, wrapping around the edges. The first move shows the cursor in the middle.
+func (m model) moveCursor(rowOffset, colOffset int) (tea.Model, tea.Cmd) {
+	if m.cursor == nil {
+		return m.showCursor()
+	}
+
+	height, width := m.field.Height(), m.field.Width()
+	m.cursor = &minesweeper.Coordinates{
+		Row: (m.cursor.Row + rowOffset + height) % height,
+		Col: (m.cursor.Col + colOffset + width) % width,
+	}
+	return m, nil
+}
+
+func (m model) setCursorRow(row int) (tea.Model, tea.Cmd) {
+	if m.cursor == nil {
+		return m.showCursor()
+	}
+	m.cursor = &minesweeper.Coordinates{Row: row, Col: m.cursor.Col}
+	return m, nil
+}
+
+func (m model) setCursorCol(col int) (tea.Model, tea.Cmd) {
+	if m.cursor == nil {
+		return m.showCursor()
+	}
+	m.cursor = &minesweeper.Coordinates{Row: m.cursor.Row, Col: col}
+	return m, nil
+}
+
+func (m model) showCursor() (tea.Model, tea.Cmd) {
+	m.cursor = &minesweeper.Coordinates{Row: m.field.Height() / 2, Col: m.field.Width() / 2}
+	return m, nil
+}
+
+// applyOnCursor runs a move on the cursor cell, or shows the cursor if it's hidden
+func (m model) applyOnCursor(action func(row, col int) (minesweeper.ChangeSet, error)) (tea.Model, tea.Cmd) {
+	if m.cursor == nil {
+		return m.showCursor()
+	}
+	if m.field.GameStatus() != minesweeper.GameOn {
+		return m, nil
+	}
+
+	_, err := action(m.cursor.Row, m.cursor.Col)
+	return m.afterMoveResult(err)
+}
+
+func (m model) handleJumpInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.jumping = false
+		m.jumpInput.Blur()
+		return m, nil
+	case "enter":
+		coord, err := m.parseJumpInput()
+		if err != nil {
+			m.jumpError = err
+			return m, nil
+		}
+		m.jumping = false
+		m.jumpInput.Blur()
+		m.cursor = &coord
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.jumpInput, cmd = m.jumpInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) parseJumpInput() (minesweeper.Coordinates, error) {
+	parts := strings.Fields(strings.ReplaceAll(m.jumpInput.Value(), ",", " "))
+	if len(parts) != 2 {
+		return minesweeper.Coordinates{}, fmt.Errorf("enter row and column, e.g. '3 5'")
+	}
+
+	row, err := strconv.Atoi(parts[0])
+	if err != nil || row < 0 || row >= m.field.Height() {
+		return minesweeper.Coordinates{}, fmt.Errorf("row must be a number between 0 and %v", m.field.Height()-1)
+	}
+	col, err := strconv.Atoi(parts[1])
+	if err != nil || col < 0 || col >= m.field.Width() {
+		return minesweeper.Coordinates{}, fmt.Errorf("col must be a number between 0 and %v", m.field.Width()-1)
+	}
+	return minesweeper.Coordinates{Row: row, Col: col}, nil
+}
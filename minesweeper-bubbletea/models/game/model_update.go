@@ -1,11 +1,15 @@
 package game
 
 import (
+	"errors"
+
 	"github.com/charmbracelet/bubbles/stopwatch"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/HuBeZa/minesweeper/minesweeper"
 	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/messages"
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/storage"
+	"github.com/HuBeZa/minesweeper/minesweeper/probability"
 )
 
 func (m model) Init() tea.Cmd {
@@ -19,28 +23,76 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.sw, cmd = m.sw.Update(msg)
 		return m, cmd
 	case tea.KeyMsg:
+		if m.jumping {
+			return m.handleJumpInput(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+n":
-			return m, messages.ShowMenu
+			return m, tea.Sequence(m.autosave, messages.ShowMenu)
 		case "ctrl+c", "ctrl+q":
-			return m, tea.Quit
+			return m, tea.Sequence(m.autosave, tea.Quit)
+		case "ctrl+w":
+			if m.field.GameStatus() != minesweeper.GameOn {
+				return m, m.watchReplay
+			}
+		case "ctrl+z":
+			return m.handleHistory(m.field.Undo)
+		case "ctrl+y":
+			return m.handleHistory(m.field.Redo)
+		default:
+			return m.handleKey(msg)
 		}
 	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionMotion && msg.Button == tea.MouseButtonNone {
+			return m.handleMouseHover(msg)
+		}
 		if m.field.GameStatus() != minesweeper.GameOn ||
 			(msg.Action != tea.MouseActionMotion && msg.Action != tea.MouseActionPress && msg.Action != tea.MouseActionRelease) ||
-			(msg.Button != tea.MouseButtonLeft && msg.Button != tea.MouseButtonRight) {
+			(msg.Button != tea.MouseButtonLeft && msg.Button != tea.MouseButtonRight && msg.Button != tea.MouseButtonMiddle) {
 			return m, nil
 		}
 
 		row, col := m.getClickedCell(msg)
+		if msg.Action == tea.MouseActionPress {
+			m.buttonsDown++
+			m.chording = m.chording || m.buttonsDown > 1 || msg.Button == tea.MouseButtonMiddle
+		}
 		if msg.Action == tea.MouseActionPress || msg.Action == tea.MouseActionMotion {
 			return m.handleMousePressed(row, col)
 		}
 		return m.handleMouseRelease(msg, row, col)
+	default:
+		if m.jumping {
+			// cursor blinking
+			var cmd tea.Cmd
+			m.jumpInput, cmd = m.jumpInput.Update(msg)
+			return m, cmd
+		}
 	}
 	return m, nil
 }
 
+// autosave keeps an unfinished game so it can be continued from the menu, and discards it once the game is over
+func (m model) autosave() tea.Msg {
+	if m.field.GameStatus() != minesweeper.GameOn {
+		storage.DeleteGame()
+	} else if m.started || m.elapsed > 0 {
+		storage.SaveGame(m.field, m.playTime(), m.assistance)
+	}
+	return nil
+}
+
+// saveReplay keeps the recording of the last game, so it can be watched from the menu
+func (m model) saveReplay() tea.Msg {
+	storage.SaveReplay(m.recorder.Recording())
+	return nil
+}
+
+func (m model) watchReplay() tea.Msg {
+	return messages.WatchReplayMsg{Recording: m.recorder.Recording()}
+}
+
 func (m model) getClickedCell(msg tea.MouseMsg) (int, int) {
 	for row := 0; row < m.field.Height(); row++ {
 		for col := 0; col < m.field.Width(); col++ {
@@ -52,6 +104,14 @@ func (m model) getClickedCell(msg tea.MouseMsg) (int, int) {
 	return -1, -1
 }
 
+func (m model) handleMouseHover(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	m.hovered = nil
+	if row, col := m.getClickedCell(msg); row >= 0 {
+		m.hovered = &minesweeper.Coordinates{Row: row, Col: col}
+	}
+	return m, nil
+}
+
 func (m model) handleMousePressed(row, col int) (tea.Model, tea.Cmd) {
 	m.pressedCell = nil
 	if row >= 0 {
@@ -61,20 +121,109 @@ func (m model) handleMousePressed(row, col int) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleMouseRelease(msg tea.MouseMsg, row, col int) (tea.Model, tea.Cmd) {
+	m.buttonsDown = max(m.buttonsDown-1, 0)
+	if m.chording {
+		if m.buttonsDown > 0 {
+			// chord when the last of the buttons is released
+			return m, nil
+		}
+		m.chording = false
+		m.pressedCell = nil
+		if row < 0 {
+			return m, nil
+		}
+		_, err := m.field.Chord(row, col)
+		return m.afterMoveResult(err)
+	}
+
 	m.pressedCell = nil
 	if row < 0 {
 		return m, nil
 	}
 
+	var err error
 	if msg.Button == tea.MouseButtonLeft {
-		m.field.Dig(row, col)
+		_, err = m.field.Dig(row, col)
 	} else if msg.Button == tea.MouseButtonRight {
-		m.field.ToggleFlag(row, col)
+		_, err = m.field.ToggleFlag(row, col)
+	}
+
+	return m.afterMoveResult(err)
+}
+
+func (m model) handleHistory(action func() (minesweeper.ChangeSet, error)) (tea.Model, tea.Cmd) {
+	prevStatus := m.field.GameStatus()
+	if _, err := action(); err != nil {
+		return m, nil
+	}
+
+	if prevStatus != minesweeper.GameOn && m.field.GameStatus() == minesweeper.GameOn {
+		// game is back on, resume stopwatch
+		m.summary = nil
+		m.refreshHeatmap()
+		return m, m.sw.Start()
+	}
+	return m.afterMove()
+}
+
+func (m model) showHint() (tea.Model, tea.Cmd) {
+	hint, err := probability.GetHint(m.field)
+	if err != nil {
+		return m, nil
+	}
+
+	m.hint = &hint
+	m.assistance.HintsUsed++
+	m.recorder.RecordHint(hint)
+	return m, nil
+}
+
+// toggleHeatmap shows or hides the mine probabilities. Showing them makes the game assisted.
+func (m model) toggleHeatmap() (tea.Model, tea.Cmd) {
+	if m.field.GameStatus() != minesweeper.GameOn && !m.heatmap {
+		return m, nil
+	}
+
+	m.heatmap = !m.heatmap
+	if m.heatmap {
+		m.assistance.HeatmapUsed = true
+	}
+	m.refreshHeatmap()
+	return m, nil
+}
+
+// explain opens the solver explanation of the board. Games explained by the solver are assisted.
+func (m model) explain() (tea.Model, tea.Cmd) {
+	if m.field.GameStatus() != minesweeper.GameOn {
+		return m, nil
 	}
 
+	m.assistance.SolverUsed = true
+	return m, func() tea.Msg {
+		return messages.ExplainMsg{Minefield: m.field, Back: m}
+	}
+}
+
+// afterMoveResult goes back to the menu, to start another game, when the mines of a no-guess minefield can't be placed
+// around the first dig. Other failed moves are ignored, like misclicks.
+func (m model) afterMoveResult(err error) (tea.Model, tea.Cmd) {
+	var infeasible *minesweeper.NoGuessInfeasibleError
+	if errors.As(err, &infeasible) {
+		return m, func() tea.Msg {
+			return messages.ShowMenuMsg{Err: err}
+		}
+	}
+	return m.afterMove()
+}
+
+func (m model) afterMove() (tea.Model, tea.Cmd) {
+	m.hint = nil
+	m.refreshHeatmap()
+
 	if m.field.GameStatus() != minesweeper.GameOn {
 		// stop stopwatch on game over
-		return m, m.sw.Stop()
+		m.summarize()
+		return m, tea.Batch(m.sw.Stop(), m.saveReplay)
 	}
 
 	if !m.started {
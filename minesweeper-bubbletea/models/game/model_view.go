@@ -3,50 +3,29 @@ package game
 import (
 	"fmt"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/board"
 )
 
 var (
-	leftHeaderStyle  = lipgloss.NewStyle().AlignHorizontal(lipgloss.Left).PaddingLeft(1)
-	rightHeaderStyle = lipgloss.NewStyle().AlignHorizontal(lipgloss.Right).PaddingRight(1)
-
-	winMessageStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#4aa45b")).Foreground(lipgloss.Color("#FFFFFF")).Bold(true).AlignHorizontal(lipgloss.Center)
-	loseMessageStyle = winMessageStyle.Background(lipgloss.Color("#ff0000"))
-	helpStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).MarginTop(1).MarginLeft(1)
-
-	fieldStyle       = lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).Padding(0, 1)
-	cellStyle        = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, false, false, true).Padding(0, 1)
-	topCellStyle     = cellStyle.UnsetBorderTop()
-	leftCellStyle    = cellStyle.UnsetBorderLeft()
-	topLeftCellStyle = leftCellStyle.UnsetBorderTop()
-
-	boldRedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")).Bold(true)
-
-	cellStatusToString = map[minesweeper.CellStatus]string{
-		minesweeper.Undugged:      "■",
-		minesweeper.NoMinesAround: colorString("□", "#808080"),
-		minesweeper.MinesAround1:  colorString("1", "#1e90ff"),
-		minesweeper.MinesAround2:  colorString("2", "#008000"),
-		minesweeper.MinesAround3:  colorString("3", "#b22222"),
-		minesweeper.MinesAround4:  colorString("4", "#4b0082"),
-		minesweeper.MinesAround5:  colorString("5", "#a52a2a"),
-		minesweeper.MinesAround6:  colorString("6", "#008080"),
-		minesweeper.MinesAround7:  colorString("7", "#afeeee"),
-		minesweeper.MinesAround8:  colorString("8", "#ffa500"),
-		minesweeper.Flagged:       colorString("?", "#ff6347"),
-		minesweeper.FlaggedWrong:  boldRedStyle.Render("X"),
-		minesweeper.Mine:          colorString("M", "#ff0000"),
-		minesweeper.Explode:       boldRedStyle.Render("Ж"),
+	jumpStyle    = lipgloss.NewStyle().MarginTop(1).MarginLeft(1)
+	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#d70000")).MarginLeft(1)
+	metricsStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")).AlignHorizontal(lipgloss.Center)
+	hintStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#d7af00")).MarginTop(1).MarginLeft(1)
+
+	hintCellColors = map[minesweeper.HintKind]lipgloss.Color{
+		minesweeper.SafeHint:  lipgloss.Color("#4aa45b"),
+		minesweeper.MineHint:  lipgloss.Color("#ff0000"),
+		minesweeper.GuessHint: lipgloss.Color("#d7af00"),
 	}
 )
 
 func (m model) View() string {
-	field := m.renderField()
-	fieldWidth := realWidthOf(field)
+	field := board.RenderField(m.field.AllCellStatus(), m.field.Topology(), m.renderCell)
+	fieldWidth := board.RealWidthOf(field)
 	header := m.renderHeader(fieldWidth)
 	footer := m.renderFooter(fieldWidth)
 
@@ -54,82 +33,126 @@ func (m model) View() string {
 		lipgloss.JoinVertical(lipgloss.Left, header, field, footer))
 }
 
-func (m model) renderField() string {
-	cells := m.field.AllCellStatus()
-	tableView := make([]string, len(cells))
-	for row := range cells {
-		tableView[row] = m.renderRow(row, cells[row])
+func (m model) renderCell(row, col int, cellStatus minesweeper.CellStatus) string {
+	style := board.CellStyle(row, col)
+	if m.isPressed(row, col, cellStatus) {
+		style = style.Reverse(true)
+	} else if m.hint != nil && m.hint.Cell.Equals(row, col) {
+		style = style.Background(hintCellColors[m.hint.Kind])
+	} else if p, found := m.probabilityAt(row, col, cellStatus); found {
+		style = style.Background(heatmapColor(p))
 	}
-
-	return fieldStyle.Render(lipgloss.JoinVertical(lipgloss.Left, tableView...))
+	cellStr := style.Render(board.CellString(cellStatus))
+	cellZoneId := cellId(row, col)
+	return m.zone.Mark(cellZoneId, cellStr)
 }
 
-func (m model) renderRow(row int, cells []minesweeper.CellStatus) string {
-	cellsStr := make([]string, len(cells))
-	for col := range cells {
-		cellsStr[col] = m.renderCell(row, col, cells[col])
+func (m model) isPressed(row, col int, cellStatus minesweeper.CellStatus) bool {
+	if m.cursor.Equals(row, col) {
+		return true
+	}
+	if m.pressedCell == nil {
+		return false
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Top, cellsStr...)
+	if m.chording {
+		// chording presses the undugged cells around the pressed cell
+		return cellStatus == minesweeper.Undugged && m.field.Topology().AreNeighbors(
+			m.field.Width(), m.field.Height(), *m.pressedCell, minesweeper.Coordinates{Row: row, Col: col})
+	}
+	return m.pressedCell.Equals(row, col) && (cellStatus == minesweeper.Undugged || cellStatus == minesweeper.Flagged)
 }
 
-func (m model) renderCell(row, col int, cellStatus minesweeper.CellStatus) string {
-	style := getCellStyle(row, col)
-	if m.pressedCell.Equals(row, col) && (cellStatus == minesweeper.Undugged || cellStatus == minesweeper.Flagged) {
-		style = style.Reverse(true)
+// probabilityAt returns the mine probability of a cell that was undugged when the heatmap was last calculated
+func (m model) probabilityAt(row, col int, cellStatus minesweeper.CellStatus) (float64, bool) {
+	if m.probabilities == nil {
+		return 0, false
 	}
-	cellStr := cellStatusToString[cellStatus]
-	cellStr = style.Render(cellStr)
-	cellZoneId := cellId(row, col)
-	return m.zone.Mark(cellZoneId, cellStr)
+	if cellStatus != minesweeper.Undugged && cellStatus != minesweeper.Mine && cellStatus != minesweeper.Explode {
+		return 0, false
+	}
+	return m.probabilities[row][col], true
 }
 
-func getCellStyle(row, col int) lipgloss.Style {
-	if col == 0 {
-		if row == 0 {
-			return topLeftCellStyle
-		}
-		return leftCellStyle
+// heatmapColor blends from green for safe cells, through yellow, to red for certain mines
+func heatmapColor(p float64) lipgloss.Color {
+	var r, g float64
+	if p < 0.5 {
+		r, g = 510*p, 200
+	} else {
+		r, g = 255, 200*(2-2*p)
 	}
-	if row == 0 {
-		return topCellStyle
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x00", int(r), int(g)))
+}
+
+// focusedCell returns the cell under the mouse, or the cursor if the mouse is outside the minefield
+func (m model) focusedCell() *minesweeper.Coordinates {
+	if m.hovered != nil {
+		return m.hovered
 	}
-	return cellStyle
+	return m.cursor
 }
 
 func (m model) renderHeader(width int) string {
 	leftHeader := fmt.Sprintf("Flags: %v", m.field.FlagsLeft())
-	rightHeader := m.sw.View()
-
-	leftHeader = leftHeaderStyle.Width(width / 2).Render(leftHeader)
-	if width%2 != 0 {
-		width++
+	if m.assistance.HintsUsed > 0 {
+		leftHeader += fmt.Sprintf(" • Hints: %v", m.assistance.HintsUsed)
+	}
+	if m.assistance.HeatmapUsed {
+		leftHeader += " • Heatmap"
 	}
-	rightHeader = rightHeaderStyle.Width(width / 2).Render(rightHeader)
-	return lipgloss.JoinHorizontal(lipgloss.Top, leftHeader, rightHeader)
+	if m.assistance.SolverUsed {
+		leftHeader += " • Solver"
+	}
+	leftHeader += board.TopologyLabel(m.field.Topology())
+	return board.RenderHeader(width, leftHeader, m.playTime().String())
 }
 
 func (m model) renderFooter(width int) string {
-	rows := make([]string, 0, 2)
+	rows := make([]string, 0, 5)
+	assisted := ""
+	if m.assistance.Assisted() {
+		assisted = " (ASSISTED)"
+	}
 	if m.field.GameStatus() == minesweeper.Won {
-		rows = append(rows, winMessageStyle.Width(width).Render("YOU WON"))
+		rows = append(rows, board.WinMessageStyle.Width(width).Render("YOU WON"+assisted))
 	} else if m.field.GameStatus() == minesweeper.Lost {
-		rows = append(rows, loseMessageStyle.Width(width).Render("YOU LOST"))
+		rows = append(rows, board.LoseMessageStyle.Width(width).Render("YOU LOST"+assisted))
+	}
+	if m.summary != nil {
+		if m.summary.metrics != "" {
+			rows = append(rows, metricsStyle.Width(width).Render(m.summary.metrics))
+		}
+		rows = append(rows, metricsStyle.Width(width).Render(m.summary.stats))
+		rows = append(rows, metricsStyle.Width(width).Render("board code: "+m.summary.code))
 	}
 
-	rows = append(rows, helpStyle.Render("ctrl-n: new game • ctrl-q: exit"))
+	if m.hint != nil {
+		rows = append(rows, hintStyle.Render("hint: "+m.hint.String()))
+	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, rows...)
-}
+	if focused := m.focusedCell(); focused != nil {
+		status := m.field.CellStatus(focused.Row, focused.Col)
+		if p, found := m.probabilityAt(focused.Row, focused.Col, status); found {
+			rows = append(rows, hintStyle.Render(fmt.Sprintf("mine chance at (%v, %v): %.0f%%", focused.Row, focused.Col, p*100)))
+		}
+	}
 
-func realWidthOf(s string) int {
-	width := strings.IndexRune(s, '\n')
-	if width < 0 {
-		return utf8.RuneCountInString(s)
+	if m.jumping {
+		rows = append(rows, jumpStyle.Render(m.jumpInput.View()))
+		if m.jumpError != nil {
+			rows = append(rows, errorStyle.Render(m.jumpError.Error()))
+		}
 	}
 
-	return utf8.RuneCountInString(s[:width])
-}
+	help := "ctrl-z/ctrl-y: undo/redo • ctrl-n: new game • ctrl-q: exit"
+	if m.field.GameStatus() != minesweeper.GameOn {
+		help = fmt.Sprintf("seed: %v • ctrl-w: watch replay • %v", m.field.Seed(), help)
+	}
+	rows = append(rows, board.HelpStyle.Render(strings.Join([]string{
+		"mouse - left: dig • right: flag • middle/both: chord",
+		"keys  - arrows/hjkl/wasd: move • space: dig • f: flag • c: chord • g: go to • ?: hint • p: heatmap • e: explain",
+		help,
+	}, "\n")))
 
-func colorString(s, color string) string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(s)
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
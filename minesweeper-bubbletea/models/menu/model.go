@@ -3,6 +3,9 @@ package menu
 import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/storage"
 )
 
 type gameType string
@@ -12,25 +15,57 @@ const (
 	intermediate gameType = "Intermediate"
 	expert       gameType = "Expert"
 	custom       gameType = "Custom"
+	boardCode    gameType = "Enter board code"
+	layoutFile   gameType = "Layout file"
+	continueGame gameType = "Continue"
+	explainGame  gameType = "Explain saved game"
+	lastReplay   gameType = "Watch last game"
 )
 
-var options = []gameType{beginner, intermediate, expert, custom}
-
 type model struct {
+	options  []gameType
 	selected int
 
 	inputs       []textinput.Model
 	focusedInput int
-	inputError error
+	inputError   error
+	// codeInput and layoutInput are the board code and the path of the layout file to play on
+	codeInput   textinput.Model
+	layoutInput textinput.Model
+
+	noGuess  bool
+	topology minesweeper.Topology
 }
 
 func NewModel() tea.Model {
+	options := []gameType{beginner, intermediate, expert, custom, boardCode, layoutFile}
+	if storage.HasSavedGame() {
+		options = append([]gameType{continueGame, explainGame}, options...)
+	}
+	if storage.HasReplay() {
+		options = append(options, lastReplay)
+	}
+
 	return model{
+		options:      options,
 		inputs:       initInputs(),
 		focusedInput: -1,
+		codeInput:    initCodeInput(),
+		layoutInput:  initLayoutInput(),
 	}
 }
 
 func (m model) selectedOption() gameType {
-	return options[m.selected]
+	return m.options[m.selected]
+}
+
+// optionInput returns the text input of the selected option, nil for options without one
+func (m *model) optionInput() *textinput.Model {
+	switch m.selectedOption() {
+	case boardCode:
+		return &m.codeInput
+	case layoutFile:
+		return &m.layoutInput
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package menu
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/HuBeZa/minesweeper/minesweeper"
 	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/messages"
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/storage"
 )
 
 func (m model) Init() tea.Cmd {
@@ -26,7 +28,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c", "ctrl+q":
 			return m, tea.Quit
 		case "down":
-			if m.selected < len(options)-1 {
+			if m.selected < len(m.options)-1 {
 				m.selected++
 				return m.onFocusChanged()
 			}
@@ -39,7 +41,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.changeFocus(+1)
 		case "shift+tab", "left":
 			return m.changeFocus(-1)
+		case "ctrl+g":
+			m.noGuess = !m.noGuess
+			return m, nil
+		case "ctrl+t":
+			switch m.topology {
+			case minesweeper.FlatTopology:
+				m.topology = minesweeper.TorusTopology
+			case minesweeper.TorusTopology:
+				m.topology = minesweeper.HexTopology
+			default:
+				m.topology = minesweeper.FlatTopology
+			}
+			return m, nil
 		case "enter":
+			// the error of the last attempt is shown until another one
+			m.inputError = nil
 			return m, m.generateMinefield
 		default:
 			return m.updateInputs(msg)
@@ -65,6 +82,11 @@ func (m model) onFocusChanged() (tea.Model, tea.Cmd) {
 	for i := range m.inputs {
 		m.inputs[i].Blur()
 	}
+	m.codeInput.Blur()
+	m.layoutInput.Blur()
+	if input := m.optionInput(); input != nil {
+		input.Focus()
+	}
 
 	if m.selectedOption() == custom {
 		if m.focusedInput == -1 {
@@ -79,6 +101,11 @@ func (m model) onFocusChanged() (tea.Model, tea.Cmd) {
 }
 
 func (m model) updateInputs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if input := m.optionInput(); input != nil {
+		var cmd tea.Cmd
+		*input, cmd = input.Update(msg)
+		return m, cmd
+	}
 	if m.selectedOption() != custom {
 		return m, nil
 	}
@@ -90,34 +117,114 @@ func (m model) updateInputs(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m model) generator() minesweeper.Generator {
+	generator := minesweeper.GameGenerator().WithTopology(m.topology)
+	if m.noGuess {
+		generator = generator.WithNoGuess(minesweeper.DefaultNoGuessLimits)
+	}
+	return generator
+}
+
 func (m model) generateMinefield() tea.Msg {
 	switch m.selectedOption() {
 	case beginner:
-		return messages.StartNewGameMsg{Minefield: minesweeper.GameGenerator().Beginner()}
+		return messages.StartNewGameMsg{Minefield: m.generator().Beginner()}
 	case intermediate:
-		return messages.StartNewGameMsg{Minefield: minesweeper.GameGenerator().Intermediate()}
+		return messages.StartNewGameMsg{Minefield: m.generator().Intermediate()}
 	case expert:
-		return messages.StartNewGameMsg{Minefield: minesweeper.GameGenerator().Expert()}
+		return messages.StartNewGameMsg{Minefield: m.generator().Expert()}
+	case continueGame:
+		minefield, state, err := storage.LoadGame()
+		if err != nil {
+			return messages.MenuErrorMsg{Err: fmt.Errorf("failed to load saved game: %w", err)}
+		}
+		return messages.StartNewGameMsg{Minefield: minefield, Elapsed: state.Elapsed, Assistance: state.Assistance, Clicks: state.Clicks}
+	case explainGame:
+		minefield, _, err := storage.LoadGame()
+		if err != nil {
+			return messages.MenuErrorMsg{Err: fmt.Errorf("failed to load saved game: %w", err)}
+		}
+		return messages.ExplainMsg{Minefield: minefield}
+	case lastReplay:
+		recording, err := storage.LoadReplay()
+		if err != nil {
+			return messages.MenuErrorMsg{Err: fmt.Errorf("failed to load replay: %w", err)}
+		}
+		return messages.WatchReplayMsg{Recording: recording}
 	case custom:
 		values, err := m.getInputValues()
 		if err != nil {
 			return messages.MenuErrorMsg{Err: err}
 		}
 
-		minefield, err := minesweeper.GameGenerator().Custom(values[width], values[height], values[mineCount])
+		generator := m.generator()
+		if seedValue, err := m.getSeedValue(); err != nil {
+			return messages.MenuErrorMsg{Err: err}
+		} else if seedValue != nil {
+			generator = generator.WithSeed(*seedValue)
+		}
+
+		minefield, err := generator.Custom(values[width], values[height], values[mineCount])
 		if err != nil {
 			return messages.MenuErrorMsg{Err: err}
 		}
 
+		return messages.StartNewGameMsg{Minefield: minefield}
+	case boardCode:
+		minefield, err := m.parseBoardCode()
+		if err != nil {
+			return messages.MenuErrorMsg{Err: err}
+		}
+		return messages.StartNewGameMsg{Minefield: minefield}
+	case layoutFile:
+		minefield, err := m.loadLayout()
+		if err != nil {
+			return messages.MenuErrorMsg{Err: err}
+		}
 		return messages.StartNewGameMsg{Minefield: minefield}
 	default:
 		return messages.MenuErrorMsg{Err: fmt.Errorf("selection unknown")}
 	}
 }
 
+// parseBoardCode recreates the board another player shared
+func (m model) parseBoardCode() (minesweeper.Minefield, error) {
+	code := strings.TrimSpace(m.codeInput.Value())
+	if code == "" {
+		return nil, fmt.Errorf("enter the code shown at the end of a game")
+	}
+
+	board, err := minesweeper.ParseBoardCode(code)
+	if err != nil {
+		return nil, err
+	}
+	return board.Minefield()
+}
+
+// loadLayout starts a game on the mines of the layout file
+func (m model) loadLayout() (minesweeper.Minefield, error) {
+	fileName := strings.TrimSpace(m.layoutInput.Value())
+	if fileName == "" {
+		return nil, fmt.Errorf("enter the path of a layout file")
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open layout: %w", err)
+	}
+	defer file.Close()
+
+	layout, err := minesweeper.ReadLayout(file)
+	if err != nil {
+		return nil, err
+	}
+	layout.Topology = m.topology
+	return layout.Minefield()
+}
+
 func (m model) getInputValues() ([]int, error) {
-	values := make([]int, len(m.inputs))
-	for i := range m.inputs {
+	values := make([]int, seed)
+	for i := range values {
 		val, err := strconv.Atoi(strings.TrimSpace(m.inputs[i].Value()))
 		if err != nil {
 			return nil, fmt.Errorf("%v must be a number", inputLabels[i])
@@ -125,6 +232,20 @@ func (m model) getInputValues() ([]int, error) {
 
 		values[i] = val
 	}
-	
+
 	return values, nil
 }
+
+// getSeedValue returns the optional seed input, nil when left empty
+func (m model) getSeedValue() (*int64, error) {
+	str := strings.TrimSpace(m.inputs[seed].Value())
+	if str == "" {
+		return nil, nil
+	}
+
+	val, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%v must be a number", inputLabels[seed])
+	}
+	return &val, nil
+}
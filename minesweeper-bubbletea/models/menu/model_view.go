@@ -12,6 +12,7 @@ const (
 	width = iota
 	height
 	mineCount
+	seed
 )
 
 var (
@@ -24,11 +25,11 @@ var (
 	errorStyle          = inputRowStyle.Foreground(lipgloss.Color("#d70000"))
 	helpStyle           = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).MarginTop(1).MarginLeft(1)
 
-	inputLabels = []string{"width", "height", "mines"}
+	inputLabels = []string{"width", "height", "mines", "seed"}
 )
 
 func initInputs() []textinput.Model {
-	inputs := make([]textinput.Model, 3)
+	inputs := make([]textinput.Model, len(inputLabels))
 
 	// set inputs common options
 	for i := range inputs {
@@ -42,16 +43,37 @@ func initInputs() []textinput.Model {
 
 	// set specific options
 	inputs[mineCount].CharLimit = 3
+	inputs[seed].CharLimit = 19
+	inputs[seed].Placeholder = "random"
+	inputs[seed].Width = 19
 
 	return inputs
 }
 
+func initCodeInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "code shown at the end of a game"
+	input.Width = 60
+	return input
+}
+
+func initLayoutInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "path to a grid, coordinates or Tatham's Mines layout"
+	input.Width = 60
+	return input
+}
+
 func (m model) View() string {
-	rows := make([]string, 0, len(options)+5)
+	rows := make([]string, 0, len(m.options)+6)
 	rows = append(rows, m.renderHeader())
 	rows = append(rows, m.renderOptions()...)
 	rows = append(rows, m.renderCustomOptions()...)
+	rows = append(rows, m.renderInputOption()...)
 	rows = append(rows, m.renderErrors()...)
+	rows = append(rows, m.renderModes())
 	rows = append(rows, m.renderHelp())
 
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
@@ -62,15 +84,15 @@ func (m model) renderHeader() string {
 }
 
 func (m model) renderOptions() []string {
-	rows := make([]string, len(options))
-	for i := range options {
+	rows := make([]string, len(m.options))
+	for i := range m.options {
 		format := "  %s"
 		style := optionStyle
 		if i == m.selected {
 			format = "• %s"
 			style = selectedOptionStyle
 		}
-		rows[i] = style.Render(fmt.Sprintf(format, options[i]))
+		rows[i] = style.Render(fmt.Sprintf(format, m.options[i]))
 	}
 	return rows
 }
@@ -93,8 +115,23 @@ func (m model) renderCustomOptions() []string {
 	}
 }
 
+func (m model) renderInputOption() []string {
+	input := m.optionInput()
+	if input == nil {
+		return nil
+	}
+
+	label := "file"
+	if m.selectedOption() == boardCode {
+		label = "code"
+	}
+	return []string{
+		inputRowStyle.Render(labelStyle.Render(label) + input.View()),
+	}
+}
+
 func (m model) renderErrors() []string {
-	if m.selectedOption() != custom || m.inputError == nil {
+	if m.inputError == nil {
 		return nil
 	}
 
@@ -103,6 +140,15 @@ func (m model) renderErrors() []string {
 	}
 }
 
+func (m model) renderModes() string {
+	noGuess := "off"
+	if m.noGuess {
+		noGuess = "on"
+	}
+	return optionStyle.MarginTop(1).Render(labelStyle.Render("no-guess mode:") + noGuess + "  " +
+		labelStyle.Render("topology:") + m.topology.String())
+}
+
 func (m model) renderHelp() string {
-	return helpStyle.Render("↑↓←→: navigate • enter: select • ctrl-q: exit")
+	return helpStyle.Render("↑↓←→: navigate • enter: select • ctrl-g: toggle no-guess • ctrl-t: change topology • ctrl-q: exit")
 }
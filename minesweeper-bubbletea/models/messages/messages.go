@@ -1,19 +1,38 @@
 package messages
 
 import (
+	"time"
+
 	"github.com/HuBeZa/minesweeper/minesweeper"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type ShowMenuMsg struct{}
+// ShowMenuMsg returns to the menu, showing Err when set
+type ShowMenuMsg struct {
+	Err error
+}
 
 func ShowMenu() tea.Msg {
 	return ShowMenuMsg{}
 }
 
-
 type StartNewGameMsg struct {
 	Minefield minesweeper.Minefield
+	// Elapsed, Assistance and Clicks are the time already played, the help already given and the clicks already made,
+	// when resuming a saved game
+	Elapsed    time.Duration
+	Assistance minesweeper.Assistance
+	Clicks     minesweeper.Clicks
+}
+
+// ExplainMsg opens the solver explanation of a board, returning to Back when done or to the menu if Back is nil
+type ExplainMsg struct {
+	Minefield minesweeper.Minefield
+	Back      tea.Model
+}
+
+type WatchReplayMsg struct {
+	Recording minesweeper.Recording
 }
 
 type MenuErrorMsg struct {
@@ -0,0 +1,82 @@
+package replay
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+const (
+	defaultSpeed = 2
+	// maxWait caps the pause between two moves, players tend to think longer than viewers want to wait
+	maxWait = 2 * time.Second
+)
+
+var speeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+type model struct {
+	recording minesweeper.Recording
+	// boards[i], flagsLeft[i] and statuses[i] are the state after i moves
+	boards    [][][]minesweeper.CellStatus
+	flagsLeft []int
+	statuses  []minesweeper.GameStatus
+	// err is set when a move could not be replayed, the replay ends before it
+	err error
+
+	step    int
+	playing bool
+	speed   int
+	// tickId identifies the running ticker, ticks of stopped tickers are ignored
+	tickId int
+}
+
+type tickMsg struct {
+	id int
+}
+
+func NewModel(recording minesweeper.Recording) tea.Model {
+	m := model{
+		recording: recording,
+		speed:     defaultSpeed,
+	}
+
+	player, err := minesweeper.NewPlayer(recording)
+	if err != nil {
+		m.err = err
+		return m
+	}
+
+	m.appendState(player.Field())
+	for !player.Done() {
+		if _, _, err := player.Step(); err != nil {
+			m.err = err
+			break
+		}
+		m.appendState(player.Field())
+	}
+
+	// start playing right away
+	m.playing = m.lastStep() > 0
+	return m
+}
+
+func (m *model) appendState(field minesweeper.Minefield) {
+	m.boards = append(m.boards, field.AllCellStatus())
+	m.flagsLeft = append(m.flagsLeft, field.FlagsLeft())
+	m.statuses = append(m.statuses, field.GameStatus())
+}
+
+// lastStep is the step after all replayable moves were applied
+func (m model) lastStep() int {
+	return len(m.boards) - 1
+}
+
+// lastMove returns the move that led to the current step, if any
+func (m model) lastMove() (minesweeper.Move, bool) {
+	if m.step == 0 {
+		return minesweeper.Move{}, false
+	}
+	return m.recording.Moves[m.step-1], true
+}
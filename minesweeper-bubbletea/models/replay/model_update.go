@@ -0,0 +1,110 @@
+package replay
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/messages"
+)
+
+func (m model) Init() tea.Cmd {
+	if !m.playing {
+		return nil
+	}
+	return m.scheduleTick()
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tickMsg:
+		if msg.id != m.tickId || !m.playing {
+			return m, nil
+		}
+		m.step++
+		if m.step >= m.lastStep() {
+			m.playing = false
+			return m, nil
+		}
+		return m, m.scheduleTick()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+n", "esc":
+			return m, messages.ShowMenu
+		case "ctrl+c", "ctrl+q":
+			return m, tea.Quit
+		case " ", "p":
+			return m.togglePlay()
+		case "right", "l":
+			return m.seek(m.step + 1)
+		case "left", "h":
+			return m.seek(m.step - 1)
+		case "]":
+			return m.seek(m.step + 10)
+		case "[":
+			return m.seek(m.step - 10)
+		case "home", "g":
+			return m.seek(0)
+		case "end", "G":
+			return m.seek(m.lastStep())
+		case "up", "+":
+			return m.changeSpeed(+1)
+		case "down", "-":
+			return m.changeSpeed(-1)
+		}
+	}
+	return m, nil
+}
+
+// scheduleTick waits for the next move, as long as it took in the recorded game
+func (m model) scheduleTick() tea.Cmd {
+	if m.step >= m.lastStep() {
+		return nil
+	}
+
+	wait := m.recording.Moves[m.step].At
+	if m.step > 0 {
+		wait -= m.recording.Moves[m.step-1].At
+	}
+	wait = min(max(wait, 0), maxWait)
+	wait = time.Duration(float64(wait) / speeds[m.speed])
+
+	id := m.tickId
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return tickMsg{id: id}
+	})
+}
+
+func (m model) togglePlay() (tea.Model, tea.Cmd) {
+	m.tickId++
+	if m.playing {
+		m.playing = false
+		return m, nil
+	}
+
+	if m.step >= m.lastStep() {
+		// replay from the start
+		m.step = 0
+	}
+	m.playing = true
+	return m, m.scheduleTick()
+}
+
+// seek moves to a step and pauses
+func (m model) seek(step int) (tea.Model, tea.Cmd) {
+	m.tickId++
+	m.playing = false
+	m.step = min(max(step, 0), max(m.lastStep(), 0))
+	return m, nil
+}
+
+func (m model) changeSpeed(change int) (tea.Model, tea.Cmd) {
+	m.speed = min(max(m.speed+change, 0), len(speeds)-1)
+	if !m.playing {
+		return m, nil
+	}
+
+	// restart the ticker with the new speed
+	m.tickId++
+	return m, m.scheduleTick()
+}
@@ -0,0 +1,100 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper-bubbletea/models/board"
+)
+
+var (
+	progressStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#008080")).MarginLeft(1)
+	moveStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")).Italic(true).MarginLeft(1)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#d70000")).MarginLeft(1)
+)
+
+func (m model) View() string {
+	if len(m.boards) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			errorStyle.Render(fmt.Sprintf("cannot replay this game: %v", m.err)),
+			board.HelpStyle.Render("ctrl-n: menu • ctrl-q: exit"))
+	}
+
+	field := board.RenderField(m.boards[m.step], m.recording.Topology, m.renderCell)
+	fieldWidth := board.RealWidthOf(field)
+	header := m.renderHeader(fieldWidth)
+	footer := m.renderFooter(fieldWidth)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, field, footer)
+}
+
+func (m model) renderCell(row, col int, cellStatus minesweeper.CellStatus) string {
+	style := board.CellStyle(row, col)
+	if move, found := m.lastMove(); found && hasCoordinates(move) && move.Row == row && move.Col == col {
+		// highlight the cell of the last move
+		style = style.Reverse(true)
+	}
+	return style.Render(board.CellString(cellStatus))
+}
+
+func hasCoordinates(move minesweeper.Move) bool {
+	return move.Action != minesweeper.UndoAction && move.Action != minesweeper.RedoAction
+}
+
+func (m model) renderHeader(width int) string {
+	state := "⏸"
+	if m.playing {
+		state = "▶"
+	}
+	leftHeader := fmt.Sprintf("Flags: %v", m.flagsLeft[m.step]) + board.TopologyLabel(m.recording.Topology)
+	return board.RenderHeader(width, leftHeader, fmt.Sprintf("%v %vx", state, speeds[m.speed]))
+}
+
+func (m model) renderFooter(width int) string {
+	rows := make([]string, 0, 5)
+	switch m.statuses[m.step] {
+	case minesweeper.Won:
+		rows = append(rows, board.WinMessageStyle.Width(width).Render("WON"))
+	case minesweeper.Lost:
+		rows = append(rows, board.LoseMessageStyle.Width(width).Render("LOST"))
+	}
+
+	rows = append(rows, m.renderProgress(width-2))
+	rows = append(rows, moveStyle.Render(m.describeMove()))
+	if m.err != nil && m.step == m.lastStep() {
+		rows = append(rows, errorStyle.Render(fmt.Sprintf("replay stopped: %v", m.err)))
+	}
+	rows = append(rows, board.HelpStyle.Render(
+		"space: play/pause • ←→: step • []: skip 10 • home/end: start/end • ↑↓: speed • ctrl-n: menu • ctrl-q: exit"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderProgress renders a scrub bar of the moves, with the current step as a knob
+func (m model) renderProgress(width int) string {
+	width = max(width, 2)
+	pos := 0
+	if m.lastStep() > 0 {
+		pos = m.step * (width - 1) / m.lastStep()
+	}
+	bar := strings.Repeat("━", pos) + "●" + strings.Repeat("─", width-pos-1)
+	return progressStyle.Render(bar)
+}
+
+func (m model) describeMove() string {
+	moveCount := len(m.recording.Moves)
+	move, found := m.lastMove()
+	if !found {
+		return fmt.Sprintf("move 0/%v", moveCount)
+	}
+
+	description := string(move.Action)
+	if hasCoordinates(move) {
+		description = fmt.Sprintf("%v (%v, %v)", move.Action, move.Row, move.Col)
+	}
+	return fmt.Sprintf("move %v/%v • %v • %v", m.step, moveCount, move.At.Round(time.Millisecond*100), description)
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+const (
+	appDir       = "minesweeper"
+	autosaveFile = "autosave.json"
+	replayFile   = "last-replay.json"
+)
+
+// path returns the path of a file in the app's config directory, creating the directory if needed
+func path(file string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configDir, appDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, file), nil
+}
+
+func exists(file string) bool {
+	p, err := path(file)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(p)
+	return err == nil
+}
+
+func write(file string, writeFunc func(w io.Writer) error) error {
+	p, err := path(file)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return writeFunc(f)
+}
+
+func read(file string, readFunc func(r io.Reader) error) error {
+	p, err := path(file)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return readFunc(f)
+}
+
+// HasSavedGame reports whether there is an autosaved game to continue
+func HasSavedGame() bool {
+	return exists(autosaveFile)
+}
+
+func SaveGame(field minesweeper.Minefield, elapsed time.Duration, assistance minesweeper.Assistance) error {
+	state := field.State()
+	state.Elapsed = elapsed
+	state.Assistance = assistance
+	return write(autosaveFile, func(w io.Writer) error {
+		return minesweeper.SaveGame(w, state)
+	})
+}
+
+func LoadGame() (field minesweeper.Minefield, state minesweeper.GameState, err error) {
+	err = read(autosaveFile, func(r io.Reader) error {
+		field, state, err = minesweeper.LoadGame(r)
+		return err
+	})
+	return field, state, err
+}
+
+func DeleteGame() error {
+	p, err := path(autosaveFile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// HasReplay reports whether there is a recording of the last finished game
+func HasReplay() bool {
+	return exists(replayFile)
+}
+
+func SaveReplay(recording minesweeper.Recording) error {
+	return write(replayFile, func(w io.Writer) error {
+		return minesweeper.WriteRecording(w, recording)
+	})
+}
+
+func LoadReplay() (recording minesweeper.Recording, err error) {
+	err = read(replayFile, func(r io.Reader) error {
+		recording, err = minesweeper.ReadRecording(r)
+		return err
+	})
+	return recording, err
+}
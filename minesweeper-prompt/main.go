@@ -2,19 +2,35 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper/probability"
 	"github.com/fatih/color"
 	"github.com/inancgumus/screen"
 )
 
+const (
+	defaultSaveFile = "minesweeper.save.json"
+	// hexColumnWidth is the width of a column of a hex grid. Tabs can't shift a row, so hex grids are aligned with
+	// spaces, and their odd rows are shifted by half a column.
+	hexColumnWidth = 4
+)
+
 var (
 	drawHeader  = true
 	headerColor = color.New(color.FgHiBlack, color.Bold)
+
+	// play time tracking, loadedElapsed is the time played before the game was loaded
+	startedAt     = time.Now()
+	loadedElapsed time.Duration
+	// hintsUsed counts the hints given, games played with hints are assisted
+	hintsUsed int
 )
 
 func main() {
@@ -23,7 +39,14 @@ func main() {
 		os.Exit(0)
 	}
 
-	field, err := generateMinefield()
+	args, opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		color.HiRed("error: %v\n", err)
+		printHelp()
+		os.Exit(1)
+	}
+
+	field, err := createMinefield(args, opts)
 	if err != nil {
 		color.HiRed("error: %v\n", err)
 		printHelp()
@@ -34,37 +57,190 @@ func main() {
 	draw(field)
 
 	reader := bufio.NewReader(os.Stdin)
-	for field.GameStatus() == minesweeper.GameOn {
-		fmt.Print("Enter command and coordinates in this format: <command row col>\n - flag: 'f 2 1'\n - unflag: 'u 7 0'\n - dig: d 3 5\nYour command: ")
+	for {
+		if field.GameStatus() == minesweeper.GameOn {
+			if field.Topology() == minesweeper.HexTopology {
+				fmt.Print("Odd rows are shifted right, cell (r, c) touches (r±1, c-1) and (r±1, c) on even rows, (r±1, c) and (r±1, c+1) on odd rows\n")
+			}
+			fmt.Print("Enter command and coordinates in this format: <command row col>\n - flag: 'f 2 1'\n - unflag: 'u 7 0'\n - dig: d 3 5\n - chord: c 4 4\n" +
+				"Or one of: 'undo', 'redo', 'hint', 'save [file]', 'new'\nYour command: ")
+		} else {
+			fmt.Print("\nEnter 'undo' to take back the last move, or anything else to exit: ")
+		}
 		text, _ := reader.ReadString('\n')
-		err := runCommand(field, text)
+		if field.GameStatus() != minesweeper.GameOn && strings.TrimSpace(text) != "undo" {
+			break
+		}
+
+		var msg string
+		if strings.TrimSpace(text) == "new" {
+			var newField minesweeper.Minefield
+			if newField, err = newGame(args, opts); err == nil {
+				field = newField
+			}
+		} else {
+			msg, err = runCommand(field, text)
+		}
+		var infeasible *minesweeper.NoGuessInfeasibleError
+		if errors.As(err, &infeasible) {
+			// the mines are placed by the first dig, which may succeed on another cell
+			err = fmt.Errorf("%w - dig another cell, or enter 'new' for another minefield", err)
+		}
 		screen.Clear()
 		draw(field)
 		if err != nil {
 			fmt.Println()
 			color.HiRed("%s\n", err)
+		} else if msg != "" {
+			fmt.Println()
+			color.HiGreen("%s\n", msg)
+		}
+	}
+}
+
+type options struct {
+	seed       *int64
+	noGuess    bool
+	topology   minesweeper.Topology
+	loadFile   string
+	layoutFile string
+}
+
+// parseOptions extracts the options from the command line arguments, returning the remaining arguments
+func parseOptions(args []string) ([]string, options, error) {
+	var opts options
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--seed":
+			if i+1 >= len(args) {
+				return nil, opts, fmt.Errorf("missing value for '--seed' option")
+			}
+			i++
+			val, err := strconv.ParseInt(args[i], 10, 64)
+			if err != nil {
+				return nil, opts, fmt.Errorf("wrong type argument 'seed', expect number")
+			}
+			opts.seed = &val
+		case "--no-guess":
+			opts.noGuess = true
+		case "--torus":
+			opts.topology = minesweeper.TorusTopology
+		case "--hex":
+			opts.topology = minesweeper.HexTopology
+		case "--load":
+			if i+1 >= len(args) {
+				return nil, opts, fmt.Errorf("missing value for '--load' option")
+			}
+			i++
+			opts.loadFile = args[i]
+		case "--layout":
+			if i+1 >= len(args) {
+				return nil, opts, fmt.Errorf("missing value for '--layout' option")
+			}
+			i++
+			opts.layoutFile = args[i]
+		default:
+			rest = append(rest, args[i])
 		}
 	}
+	return rest, opts, nil
 }
 
-func generateMinefield() (minesweeper.Minefield, error) {
-	if len(os.Args) < 2 {
-		return minesweeper.GameGenerator().Beginner(), nil
+// newGame replaces the current game with a new one, created from the same command line
+func newGame(args []string, opts options) (minesweeper.Minefield, error) {
+	prevElapsed, prevHints := loadedElapsed, hintsUsed
+	loadedElapsed, hintsUsed = 0, 0
+	field, err := createMinefield(args, opts)
+	if err != nil {
+		loadedElapsed, hintsUsed = prevElapsed, prevHints
+		return nil, err
 	}
+	startedAt = time.Now()
+	return field, nil
+}
 
-	switch cmd := strings.ToLower(os.Args[1]); cmd {
+func createMinefield(args []string, opts options) (minesweeper.Minefield, error) {
+	if opts.loadFile != "" {
+		return loadMinefield(opts.loadFile)
+	}
+	if opts.layoutFile != "" {
+		return loadLayout(opts.layoutFile, opts)
+	}
+	return generateMinefield(args, opts)
+}
+
+func loadMinefield(fileName string) (minesweeper.Minefield, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	field, state, err := minesweeper.LoadGame(file)
+	if err != nil {
+		return nil, err
+	}
+	loadedElapsed = state.Elapsed
+	hintsUsed = state.HintsUsed
+	return field, nil
+}
+
+func loadLayout(fileName string, opts options) (minesweeper.Minefield, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	layout, err := minesweeper.ReadLayout(file)
+	if err != nil {
+		return nil, err
+	}
+	layout.Topology = opts.topology
+	return layout.Minefield()
+}
+
+func saveMinefield(field minesweeper.Minefield, fileName string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	state := field.State()
+	state.Elapsed = loadedElapsed + time.Since(startedAt)
+	state.HintsUsed = hintsUsed
+	return minesweeper.SaveGame(file, state)
+}
+
+func generateMinefield(args []string, opts options) (minesweeper.Minefield, error) {
+	generator := minesweeper.GameGenerator()
+	if opts.seed != nil {
+		generator = generator.WithSeed(*opts.seed)
+	}
+	if opts.noGuess {
+		generator = generator.WithNoGuess(minesweeper.DefaultNoGuessLimits)
+	}
+	generator = generator.WithTopology(opts.topology)
+
+	if len(args) < 1 {
+		return generator.Beginner(), nil
+	}
+
+	switch cmd := strings.ToLower(args[0]); cmd {
 	case "beginner", "b":
-		return minesweeper.GameGenerator().Beginner(), nil
+		return generator.Beginner(), nil
 	case "intermediate", "i":
-		return minesweeper.GameGenerator().Intermediate(), nil
+		return generator.Intermediate(), nil
 	case "expert", "e":
-		return minesweeper.GameGenerator().Expert(), nil
+		return generator.Expert(), nil
 	case "custom", "c":
-		if len(os.Args) < 5 {
+		if len(args) < 4 {
 			return nil, fmt.Errorf("not enough arguments for 'custom' command")
 		}
 
-		strArgs := os.Args[2:]
+		strArgs := args[1:]
 		intArgs := make([]int, 3)
 		for i, argName := range []string{"width", "height", "mines-count"} {
 			intArg, err := strconv.Atoi(strArgs[i])
@@ -73,7 +249,7 @@ func generateMinefield() (minesweeper.Minefield, error) {
 			}
 			intArgs[i] = intArg
 		}
-		return minesweeper.GameGenerator().Custom(intArgs[0], intArgs[1], intArgs[2])
+		return generator.Custom(intArgs[0], intArgs[1], intArgs[2])
 	default:
 		return nil, fmt.Errorf("unknown command '%v'", cmd)
 	}
@@ -84,46 +260,85 @@ func printHelp() {
 	if len(os.Args) > 0 {
 		cmd = os.Args[0]
 	}
-	fmt.Printf("usage: %v [-h | --help]\n" +
-		"                              <command> [<args>]\n" +
-		"commands:\n" +
-		"\tbeginner | b\n" +
-		"\tintermediate | i\n" +
-		"\texpert | e\n" +
-		"\tcustom | c <width> <height> <mines-count>\n", cmd)
+	fmt.Printf("usage: %v [-h | --help]\n"+
+		"                              [--seed <seed>] [--no-guess] [--torus | --hex] <command> [<args>]\n"+
+		"                              --load <file>\n"+
+		"                              --layout <file>\n"+
+		"commands:\n"+
+		"\tbeginner | b\n"+
+		"\tintermediate | i\n"+
+		"\texpert | e\n"+
+		"\tcustom | c <width> <height> <mines-count>\n"+
+		"options:\n"+
+		"\t--seed <seed>\tgenerate the same mine layout as a previous game\n"+
+		"\t--no-guess\tgenerate a minefield that can be solved without guessing\n"+
+		"\t--torus\t\twrap the edges around, so every cell has eight neighbours\n"+
+		"\t--hex\t\tplay on hexagonal cells with six neighbours, odd rows are shifted half a cell to the right\n"+
+		"\t--load <file>\tcontinue a game saved with the 'save' command\n"+
+		"\t--layout <file>\tplay on the mines of a layout file - an ascii grid ('*' for a mine, '.' for empty),\n"+
+		"\t\t\ta '<width>x<height>' line followed by a '<row>,<col>' line per mine, or a Simon Tatham's Mines game id\n", cmd)
 }
 
-func runCommand(field minesweeper.Minefield, input string) error {
+// runCommand applies a command on the minefield, returning an optional message for the player
+func runCommand(field minesweeper.Minefield, input string) (string, error) {
 	input = strings.TrimSpace(input)
 
+	switch input {
+	case "undo":
+		_, err := field.Undo()
+		return "", err
+	case "redo":
+		_, err := field.Redo()
+		return "", err
+	case "hint":
+		hint, err := probability.GetHint(field)
+		if err != nil {
+			return "", err
+		}
+		hintsUsed++
+		return fmt.Sprintf("hint: %v", hint), nil
+	}
+
 	parts := strings.Split(input, " ")
+	if parts[0] == "save" {
+		fileName := defaultSaveFile
+		if len(parts) > 1 {
+			fileName = strings.Join(parts[1:], " ")
+		}
+		if err := saveMinefield(field, fileName); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("game saved to '%s'", fileName), nil
+	}
 	if len(parts) != 3 {
-		return fmt.Errorf("wrong format")
+		return "", fmt.Errorf("wrong format")
 	}
 
 	cmd := parts[0]
-	if cmd != "f" && cmd != "u" && cmd != "d" {
-		return fmt.Errorf("wrong command name '%s', use 'f', 'u' or 'd'", parts[0])
+	if cmd != "f" && cmd != "u" && cmd != "d" && cmd != "c" {
+		return "", fmt.Errorf("wrong command name '%s', use 'f', 'u', 'd' or 'c'", parts[0])
 	}
 
 	row, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return fmt.Errorf("wrong row coordinate format '%s', use a number", parts[1])
+		return "", fmt.Errorf("wrong row coordinate format '%s', use a number", parts[1])
 	}
 	col, err := strconv.Atoi(parts[2])
 	if err != nil {
-		return fmt.Errorf("wrong col coordinate format '%s', use a number", parts[2])
+		return "", fmt.Errorf("wrong col coordinate format '%s', use a number", parts[2])
 	}
 
 	switch cmd {
 	case "f":
-		field.Flag(row, col)
+		_, err = field.Flag(row, col)
 	case "u":
-		field.Unflag(row, col)
+		_, err = field.Unflag(row, col)
 	case "d":
-		field.Dig(row, col)
+		_, err = field.Dig(row, col)
+	case "c":
+		_, err = field.Chord(row, col)
 	}
-	return nil
+	return "", err
 }
 
 func draw(f minesweeper.Minefield) {
@@ -131,17 +346,33 @@ func draw(f minesweeper.Minefield) {
 	cells := f.AllCellStatus()
 
 	if drawHeader {
-		sb.WriteString(fmt.Sprintf(" 🚩 = %v\n", f.FlagsLeft()))
+		sb.WriteString(fmt.Sprintf(" 🚩 = %v\t", f.FlagsLeft()))
+		sb.WriteString(headerColor.Sprintf("seed: %v", f.Seed()))
+		if f.Topology() != minesweeper.FlatTopology {
+			sb.WriteString(headerColor.Sprintf("\t%v", f.Topology()))
+		}
+		if hintsUsed > 0 {
+			sb.WriteString(headerColor.Sprintf("\thints: %v (assisted)", hintsUsed))
+		}
+		sb.WriteString("\n")
 
-		sb.WriteString(headerColor.Sprintf("%8v", "|\t"))
-		for col := range cells[0] {
-			if col > 0 {
-				sb.WriteString(headerColor.Sprint("\t"))
+		if f.Topology() == minesweeper.HexTopology {
+			sb.WriteString(headerColor.Sprintf("%8v", "| "))
+			for col := range cells[0] {
+				sb.WriteString(headerColor.Sprintf("%-*v", hexColumnWidth, col))
 			}
+			sb.WriteString(headerColor.Sprint(strings.Repeat(" ", hexColumnWidth/2) + "| \n"))
+		} else {
+			sb.WriteString(headerColor.Sprintf("%8v", "|\t"))
+			for col := range cells[0] {
+				if col > 0 {
+					sb.WriteString(headerColor.Sprint("\t"))
+				}
 
-			sb.WriteString(headerColor.Sprintf("%v", col))
+				sb.WriteString(headerColor.Sprintf("%v", col))
+			}
+			sb.WriteString(headerColor.Sprint("\t| \n"))
 		}
-		sb.WriteString(headerColor.Sprint("\t| \n"))
 	}
 
 	for row := range cells {
@@ -149,6 +380,11 @@ func draw(f minesweeper.Minefield) {
 			sb.WriteString(headerColor.Sprintf(" %-4v ", row))
 		}
 
+		if f.Topology() == minesweeper.HexTopology {
+			sb.WriteString(hexRow(cells[row], row%2 == 1))
+			continue
+		}
+
 		sb.WriteString("|\t")
 		for col := range cells[row] {
 			if col > 0 {
@@ -168,10 +404,43 @@ func draw(f minesweeper.Minefield) {
 		c := color.New(color.FgMagenta, color.Bold)
 		sb.WriteString("😎 😎 😎 😎 😎 😎 😎 😎 😎 😎 😎 " + c.Sprint("YOU WON") + " 😎 😎 😎 😎 😎 😎 😎 😎 😎 😎 😎")
 	}
+	if f.GameStatus() != minesweeper.GameOn {
+		if metrics, err := minesweeper.AnalyzeMinefield(f); err == nil {
+			sb.WriteString("\n" + headerColor.Sprint(metrics.String()))
+		}
+	}
 
 	fmt.Println(sb.String())
 }
 
+// hexRow draws a row of a hex grid, shifting odd rows by half a column
+func hexRow(cells []minesweeper.CellStatus, shifted bool) string {
+	var sb strings.Builder
+	halfColumn := strings.Repeat(" ", hexColumnWidth/2)
+	sb.WriteString("| ")
+	if shifted {
+		sb.WriteString(halfColumn)
+	}
+	for _, status := range cells {
+		sb.WriteString(cellStatusToString(status))
+		sb.WriteString(strings.Repeat(" ", hexColumnWidth-cellWidth(status)))
+	}
+	if !shifted {
+		sb.WriteString(halfColumn)
+	}
+	sb.WriteString("| \n")
+	return sb.String()
+}
+
+// cellWidth returns the number of columns a cell takes on the terminal, emojis are twice as wide as other symbols
+func cellWidth(status minesweeper.CellStatus) int {
+	switch status {
+	case minesweeper.Flagged, minesweeper.Mine, minesweeper.Explode:
+		return 2
+	}
+	return 1
+}
+
 func cellStatusToString(status minesweeper.CellStatus) string {
 	switch status {
 	case minesweeper.Undugged:
@@ -0,0 +1,301 @@
+package minesweeper
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"slices"
+	"time"
+)
+
+// boardCodeVersion is the version of the board code format, bumped on incompatible changes.
+// Version 2 added the topology, version 1 codes are of flat boards.
+const boardCodeVersion = 2
+
+// board code header bits, the low bits hold the version
+const (
+	versionMask   = 0b111
+	minesBit      = 1 << 3
+	firstClickBit = 1 << 4
+	bitmapBit     = 1 << 5 // mines are a bitmap rather than a list
+	noGuessBit    = 1 << 5 // shared with bitmapBit, boards with mines are never generated
+	policyShift   = 6
+)
+
+const (
+	checksumSize = 2
+	// maxCodeArea keeps codes from asking for boards too big to create
+	maxCodeArea = 1 << 24
+)
+
+// Board - everything needed to recreate a minefield, so other players can play on the same board
+type Board struct {
+	Width    int
+	Height   int
+	Topology Topology
+	// Mines is the layout of the board, nil when the mines are not placed yet and are generated from the seed
+	Mines MineList
+	// MineCount, Seed, FirstClickPolicy and NoGuess generate the layout of boards without mines
+	MineCount        int
+	Seed             int64
+	FirstClickPolicy FirstClickPolicy
+	NoGuess          *NoGuessLimits
+	// FirstClick is dug on the recreated minefield, so every player starts where the board's first player did.
+	// It is also what places the mines of generated boards, other first clicks would generate another layout.
+	FirstClick *Coordinates
+}
+
+// BoardOf returns the board of a minefield, with an optional first click.
+// Boards of minefields that place their mines on the first dig are generated from the seed until then.
+func BoardOf(f Minefield, firstClick *Coordinates) Board {
+	state := f.State()
+	board := Board{
+		Width:      state.Width,
+		Height:     state.Height,
+		Topology:   state.Topology,
+		MineCount:  state.MineCount,
+		FirstClick: firstClick,
+	}
+
+	if len(state.Mines) == 0 {
+		board.Seed = state.Seed
+		board.FirstClickPolicy = state.FirstClick
+		board.NoGuess = state.NoGuess
+		return board
+	}
+
+	board.Mines = NewMineList(state.Width, state.Height, len(state.Mines))
+	for _, coord := range state.Mines {
+		board.Mines.Add(coord.Row, coord.Col)
+	}
+	return board
+}
+
+// Minefield creates a new game on the board
+func (b Board) Minefield() (Minefield, error) {
+	var f Minefield
+	var err error
+	if b.Mines != nil {
+		f, err = Layout{Width: b.Width, Height: b.Height, Mines: b.Mines, Topology: b.Topology}.Minefield()
+	} else {
+		generator := GameGenerator().WithSeed(b.Seed).WithFirstClickPolicy(b.FirstClickPolicy).WithTopology(b.Topology)
+		if b.NoGuess != nil {
+			generator = generator.WithNoGuess(*b.NoGuess)
+		}
+		f, err = generator.Custom(b.Width, b.Height, b.MineCount)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if b.FirstClick != nil {
+		if _, err := f.Dig(b.FirstClick.Row, b.FirstClick.Col); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Code encodes the board as a short url safe string, ending with a checksum that catches mistyped codes
+func (b Board) Code() string {
+	header := byte(boardCodeVersion)
+	data := make([]byte, 1, 32)
+	data = binary.AppendUvarint(data, uint64(b.Width))
+	data = binary.AppendUvarint(data, uint64(b.Height))
+	data = binary.AppendUvarint(data, uint64(b.Topology))
+
+	if b.Mines != nil {
+		header |= minesBit
+		list, bitmap := encodeMineList(b.Width, b.Mines), encodeMineBitmap(b.Width, b.Height, b.Mines)
+		if len(bitmap) < len(list) {
+			header |= bitmapBit
+			data = append(data, bitmap...)
+		} else {
+			data = append(data, list...)
+		}
+	} else {
+		header |= byte(b.FirstClickPolicy) << policyShift
+		data = binary.AppendUvarint(data, uint64(b.MineCount))
+		data = binary.AppendVarint(data, b.Seed)
+		if b.NoGuess != nil {
+			header |= noGuessBit
+			data = binary.AppendUvarint(data, uint64(b.NoGuess.MaxAttempts))
+			data = binary.AppendUvarint(data, uint64(b.NoGuess.Timeout.Milliseconds()))
+		}
+	}
+
+	if b.FirstClick != nil {
+		header |= firstClickBit
+		data = binary.AppendUvarint(data, uint64(b.FirstClick.Row))
+		data = binary.AppendUvarint(data, uint64(b.FirstClick.Col))
+	}
+
+	data[0] = header
+	data = binary.BigEndian.AppendUint16(data, uint16(crc32.ChecksumIEEE(data)))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// encodeMineList writes the number of mines, then the gaps between the cell indexes of the mines
+func encodeMineList(width int, mines MineList) []byte {
+	coords := slices.SortedFunc(slices.Values(mines.Coordinates()), compareCoordinates)
+	data := binary.AppendUvarint(nil, uint64(len(coords)))
+	prev := -1
+	for _, coord := range coords {
+		index := coord.Row*width + coord.Col
+		data = binary.AppendUvarint(data, uint64(index-prev-1))
+		prev = index
+	}
+	return data
+}
+
+// encodeMineBitmap writes a bit per cell, row by row
+func encodeMineBitmap(width, height int, mines MineList) []byte {
+	data := make([]byte, (width*height+7)/8)
+	for _, coord := range mines.Coordinates() {
+		index := coord.Row*width + coord.Col
+		data[index/8] |= 0x80 >> (index % 8)
+	}
+	return data
+}
+
+// ParseBoardCode decodes a board code written by Board.Code. No-guess limits above DefaultNoGuessLimits are lowered to them.
+func ParseBoardCode(code string) (Board, error) {
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil || len(data) < 1+checksumSize {
+		return Board{}, &InvalidBoardCodeError{Reason: "not a board code"}
+	}
+	data, checksum := data[:len(data)-checksumSize], binary.BigEndian.Uint16(data[len(data)-checksumSize:])
+	if uint16(crc32.ChecksumIEEE(data)) != checksum {
+		return Board{}, &InvalidBoardCodeError{Reason: "checksum mismatch, the code is mistyped"}
+	}
+
+	header := data[0]
+	version := header & versionMask
+	if version != 1 && version != boardCodeVersion {
+		return Board{}, &InvalidBoardCodeError{Reason: "unsupported board code version"}
+	}
+
+	r := &codeReader{data: data[1:]}
+	board := Board{
+		Width:  r.uint(),
+		Height: r.uint(),
+	}
+	if version >= 2 {
+		board.Topology = Topology(r.uint())
+	}
+	if !board.Topology.isValid() {
+		return Board{}, &InvalidBoardCodeError{Reason: "unknown topology"}
+	}
+	if r.err != nil || board.Width <= 0 || board.Height <= 0 || board.Width*board.Height > maxCodeArea {
+		return Board{}, &InvalidBoardCodeError{Reason: "invalid dimensions"}
+	}
+
+	if header&minesBit != 0 {
+		if header&bitmapBit != 0 {
+			board.Mines = r.mineBitmap(board.Width, board.Height)
+		} else {
+			board.Mines = r.mineList(board.Width, board.Height)
+		}
+		if board.Mines != nil {
+			board.MineCount = board.Mines.Len()
+		}
+	} else {
+		board.FirstClickPolicy = FirstClickPolicy(header >> policyShift)
+		board.MineCount = r.uint()
+		board.Seed = r.int()
+		if header&noGuessBit != 0 {
+			// codes are shared by other players, so their limits can't make the search for a layout any longer than usual
+			board.NoGuess = &NoGuessLimits{
+				MaxAttempts: min(r.uint(), DefaultNoGuessLimits.MaxAttempts),
+				Timeout:     min(time.Duration(r.uint())*time.Millisecond, DefaultNoGuessLimits.Timeout),
+			}
+		}
+	}
+
+	if header&firstClickBit != 0 {
+		board.FirstClick = &Coordinates{Row: r.uint(), Col: r.uint()}
+	}
+	if r.err != nil {
+		return Board{}, r.err
+	}
+	if len(r.data) > 0 {
+		return Board{}, &InvalidBoardCodeError{Reason: "unexpected data at the end of the code"}
+	}
+	return board, nil
+}
+
+// codeReader reads the fields of a board code, keeping the first error
+type codeReader struct {
+	data []byte
+	err  error
+}
+
+func (r *codeReader) fail(reason string) {
+	if r.err == nil {
+		r.err = &InvalidBoardCodeError{Reason: reason}
+	}
+}
+
+func (r *codeReader) uint() int {
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail("truncated code")
+		return 0
+	}
+	if value > 1<<31 {
+		r.fail("number out of range")
+		return 0
+	}
+	r.data = r.data[n:]
+	return int(value)
+}
+
+func (r *codeReader) int() int64 {
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail("truncated code")
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *codeReader) mineList(width, height int) MineList {
+	count := r.uint()
+	if r.err != nil || count > len(r.data) {
+		// every mine takes at least a byte
+		r.fail("truncated code")
+		return nil
+	}
+
+	mines := NewMineList(width, height, count)
+	index := -1
+	for range count {
+		index += r.uint() + 1
+		if r.err != nil || index >= width*height {
+			r.fail("mine is outside the minefield")
+			return nil
+		}
+		coord := indexToCoordinates(index, width)
+		mines.Add(coord.Row, coord.Col)
+	}
+	return mines
+}
+
+func (r *codeReader) mineBitmap(width, height int) MineList {
+	size := (width*height + 7) / 8
+	if size > len(r.data) {
+		r.fail("truncated code")
+		return nil
+	}
+
+	mines := NewMineList(width, height, 0)
+	for index := range width * height {
+		if r.data[index/8]&(0x80>>(index%8)) != 0 {
+			coord := indexToCoordinates(index, width)
+			mines.Add(coord.Row, coord.Col)
+		}
+	}
+	r.data = r.data[size:]
+	return mines
+}
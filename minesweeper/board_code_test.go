@@ -0,0 +1,141 @@
+package minesweeper
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+var topologies = []Topology{FlatTopology, TorusTopology, HexTopology}
+
+func sortedMines(mines MineList) []Coordinates {
+	if mines == nil {
+		return nil
+	}
+	return slices.SortedFunc(slices.Values(mines.Coordinates()), compareCoordinates)
+}
+
+// checkBoardRoundTrip encodes a board and verifies it decodes to the same board
+func checkBoardRoundTrip(t *testing.T, board Board) {
+	t.Helper()
+	code := board.Code()
+	parsed, err := ParseBoardCode(code)
+	if err != nil {
+		t.Fatalf("parsing %v: %v", code, err)
+	}
+
+	if !slices.Equal(sortedMines(parsed.Mines), sortedMines(board.Mines)) {
+		t.Errorf("mines are %v, expected %v", sortedMines(parsed.Mines), sortedMines(board.Mines))
+	}
+	parsed.Mines, board.Mines = nil, nil
+	if !reflect.DeepEqual(parsed, board) {
+		t.Errorf("board is %+v, expected %+v", parsed, board)
+	}
+}
+
+func TestBoardCodeRoundTrip(t *testing.T) {
+	for _, topology := range topologies {
+		t.Run(topology.String(), func(t *testing.T) {
+			t.Run("mine list", func(t *testing.T) {
+				mines := NewMineList(30, 16, 3)
+				mines.Add(0, 0)
+				mines.Add(7, 12)
+				mines.Add(15, 29)
+				checkBoardRoundTrip(t, Board{Width: 30, Height: 16, Topology: topology, Mines: mines, MineCount: 3})
+			})
+
+			t.Run("mine bitmap", func(t *testing.T) {
+				mines := NewMineList(9, 9, 40)
+				for index := 0; index < 81; index += 2 {
+					coord := indexToCoordinates(index, 9)
+					mines.Add(coord.Row, coord.Col)
+				}
+				checkBoardRoundTrip(t, Board{Width: 9, Height: 9, Topology: topology, Mines: mines, MineCount: 41,
+					FirstClick: &Coordinates{Row: 0, Col: 1}})
+			})
+
+			t.Run("generated", func(t *testing.T) {
+				checkBoardRoundTrip(t, Board{Width: 16, Height: 16, Topology: topology, MineCount: 40, Seed: -42,
+					FirstClickPolicy: FirstClickOpening, FirstClick: &Coordinates{Row: 8, Col: 3}})
+			})
+
+			t.Run("no guess", func(t *testing.T) {
+				limits := NoGuessLimits{MaxAttempts: 100, Timeout: time.Second}
+				checkBoardRoundTrip(t, Board{Width: 9, Height: 9, Topology: topology, MineCount: 10, Seed: 7,
+					FirstClickPolicy: FirstClickSafe, NoGuess: &limits})
+			})
+		})
+	}
+}
+
+func TestBoardCodeMinefield(t *testing.T) {
+	for _, topology := range topologies {
+		t.Run(topology.String(), func(t *testing.T) {
+			f, err := GameGenerator().WithSeed(3).WithTopology(topology).Custom(9, 9, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			first := &Coordinates{Row: 4, Col: 4}
+			if _, err := f.Dig(first.Row, first.Col); err != nil {
+				t.Fatal(err)
+			}
+
+			board, err := ParseBoardCode(BoardOf(f, first).Code())
+			if err != nil {
+				t.Fatal(err)
+			}
+			recreated, err := board.Minefield()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recreated.Topology() != topology {
+				t.Errorf("recreated a %v minefield, expected %v", recreated.Topology(), topology)
+			}
+			if !reflect.DeepEqual(recreated.AllCellStatus(), f.AllCellStatus()) {
+				t.Error("the recreated minefield differs from the original")
+			}
+		})
+	}
+}
+
+func TestParseBoardCodeClampsNoGuessLimits(t *testing.T) {
+	limits := NoGuessLimits{MaxAttempts: 1 << 30, Timeout: time.Hour}
+	code := Board{Width: 9, Height: 9, MineCount: 10, NoGuess: &limits}.Code()
+
+	board, err := ParseBoardCode(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *board.NoGuess != DefaultNoGuessLimits {
+		t.Errorf("no-guess limits are %+v, expected %+v", *board.NoGuess, DefaultNoGuessLimits)
+	}
+}
+
+func TestParseBoardCodeErrors(t *testing.T) {
+	valid := Board{Width: 9, Height: 9, MineCount: 10, Seed: 1}.Code()
+	mistyped := []byte(valid)
+	mistyped[3] ^= 1
+
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!!"},
+		{"mistyped", string(mistyped)},
+		{"unknown topology", Board{Width: 9, Height: 9, Topology: 7, MineCount: 10}.Code()},
+		{"invalid dimensions", Board{Width: 0, Height: 9, MineCount: 10}.Code()},
+		{"too large", Board{Width: 1 << 13, Height: 1 << 13, MineCount: 10}.Code()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseBoardCode(test.code)
+			var codeErr *InvalidBoardCodeError
+			if !errors.As(err, &codeErr) {
+				t.Errorf("expected an invalid board code error, got %v", err)
+			}
+		})
+	}
+}
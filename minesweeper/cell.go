@@ -1,14 +1,9 @@
 package minesweeper
 
+// cell is kept small and pointer free, the minefield holds all of its cells in a single slice
 type cell struct {
+	minesAround uint8
 	isMine      bool
-	minesAround int
 	isFlagged   bool
 	isDug       bool
 }
-
-func newCell(isMine bool) *cell {
-	return &cell{
-		isMine: isMine,
-	}
-}
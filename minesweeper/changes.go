@@ -0,0 +1,94 @@
+package minesweeper
+
+import (
+	"slices"
+)
+
+// CellChange - a cell whose status was changed by a move
+type CellChange struct {
+	Coordinates
+	Before CellStatus
+	After  CellStatus
+}
+
+// ChangeSet holds every cell a move changed, including the cascade of a dig, the mines and wrong flags revealed on loss,
+// and the mines flagged on win. Cells are listed once, in the order they were changed, and the cells revealed by
+// the end of the game last.
+type ChangeSet []CellChange
+
+func (c ChangeSet) Coordinates() []Coordinates {
+	res := make([]Coordinates, len(c))
+	for i := range c {
+		res[i] = c[i].Coordinates
+	}
+	return res
+}
+
+// Contains reports whether the cell was changed
+func (c ChangeSet) Contains(row, col int) bool {
+	return slices.ContainsFunc(c, func(change CellChange) bool {
+		return change.Row == row && change.Col == col
+	})
+}
+
+// doMove runs a mutating operation as a single move and returns the cells it changed.
+// Nested moves (e.g. Chord calling Dig) are part of the outermost move, and return no changes of their own.
+func (f *minefield) doMove(operation func() error) (ChangeSet, error) {
+	if f.current != nil {
+		return nil, operation()
+	}
+
+	f.beginMove()
+	err := operation()
+	m := f.endMove()
+	if err != nil {
+		return nil, err
+	}
+	return f.changeSet(m, false), nil
+}
+
+// changeSet returns the cells changed by a move, or by undoing it.
+// The status of mines and flags depends on the game status, so they change when the game ends or is resumed.
+func (f *minefield) changeSet(m *move, undo bool) ChangeSet {
+	if m == nil {
+		return ChangeSet{}
+	}
+
+	statusBefore, statusAfter := m.statusBefore, m.statusAfter
+	if undo {
+		statusBefore, statusAfter = statusAfter, statusBefore
+	}
+
+	capacity := len(m.changes)
+	if statusBefore != statusAfter {
+		capacity += f.mineCount + len(f.flags)
+	}
+	res := make(ChangeSet, 0, capacity)
+	add := func(index int, before, after cell) {
+		change := CellChange{
+			Coordinates: f.coordinates(index),
+			Before:      cellStatusOf(before, statusBefore),
+			After:       cellStatusOf(after, statusAfter),
+		}
+		if change.Before != change.After {
+			res = append(res, change)
+		}
+	}
+
+	// the cells are in their state after the move, or after undoing it
+	for _, change := range m.changes {
+		after := f.cells[change.index]
+		add(int(change.index), after.flipped(change.flipped), after)
+	}
+
+	if statusBefore != statusAfter {
+		f.markTouched(m, true)
+		for i, c := range f.cells {
+			if !f.touched[i] && (c.isMine || c.isFlagged) {
+				add(i, c, c)
+			}
+		}
+		f.markTouched(m, false)
+	}
+	return res
+}
@@ -0,0 +1,137 @@
+package minesweeper
+
+import (
+	"testing"
+)
+
+// checkChangeSet verifies the change set lists exactly the cells whose status differs between the boards, once each
+func checkChangeSet(t *testing.T, before, after [][]CellStatus, changes ChangeSet) {
+	t.Helper()
+	seen := make(map[Coordinates]bool, len(changes))
+	for _, change := range changes {
+		if seen[change.Coordinates] {
+			t.Errorf("(%v, %v) is listed twice", change.Row, change.Col)
+		}
+		seen[change.Coordinates] = true
+
+		if change.Before != before[change.Row][change.Col] || change.After != after[change.Row][change.Col] {
+			t.Errorf("(%v, %v) changed from %v to %v, listed as %v to %v", change.Row, change.Col,
+				before[change.Row][change.Col], after[change.Row][change.Col], change.Before, change.After)
+		}
+	}
+
+	for row := range after {
+		for col := range after[row] {
+			if before[row][col] != after[row][col] && !seen[Coordinates{Row: row, Col: col}] {
+				t.Errorf("(%v, %v) changed from %v to %v, but is missing", row, col, before[row][col], after[row][col])
+			}
+		}
+	}
+}
+
+// checkMove makes a move, and verifies its change set and the change sets of undoing and redoing it
+func checkMove(t *testing.T, f Minefield, move func() (ChangeSet, error)) {
+	t.Helper()
+	before := f.AllCellStatus()
+	changes, err := move()
+	if err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	after := f.AllCellStatus()
+	checkChangeSet(t, before, after, changes)
+
+	undone, err := f.Undo()
+	if err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	checkChangeSet(t, after, before, undone)
+
+	redone, err := f.Redo()
+	if err != nil {
+		t.Fatalf("redo failed: %v", err)
+	}
+	checkChangeSet(t, before, after, redone)
+}
+
+// newChangesTestMinefield creates a 6x5 minefield with mines at (0, 5), (1, 5), (4, 0), and (4, 5),
+// so digging the top left corner floods most of the board
+func newChangesTestMinefield(t *testing.T) Minefield {
+	t.Helper()
+	mines := NewMineList(6, 5, 4)
+	for _, coord := range []Coordinates{{0, 5}, {1, 5}, {4, 0}, {4, 5}} {
+		if err := mines.Add(coord.Row, coord.Col); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewMinefield(6, 5, mines)
+}
+
+func TestChangeSetOfFlood(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	checkMove(t, f, func() (ChangeSet, error) { return f.Dig(0, 0) })
+	if f.GameStatus() != GameOn {
+		t.Fatalf("game status is %v after the flood", f.GameStatus())
+	}
+	if f.CellStatus(3, 3) != NoMinesAround {
+		t.Errorf("(3, 3) is %v, the flood didn't reach it", f.CellStatus(3, 3))
+	}
+}
+
+func TestChangeSetOfFlag(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	checkMove(t, f, func() (ChangeSet, error) { return f.Flag(0, 5) })
+	checkMove(t, f, func() (ChangeSet, error) { return f.Unflag(0, 5) })
+}
+
+func TestChangeSetOfLoss(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	// a wrong flag and a right one, both revealed by the loss
+	if _, err := f.Flag(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Flag(1, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMove(t, f, func() (ChangeSet, error) { return f.Dig(4, 0) })
+	if f.GameStatus() != Lost {
+		t.Fatalf("game status is %v after digging a mine", f.GameStatus())
+	}
+}
+
+func TestChangeSetOfLossByChord(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	if _, err := f.Dig(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	// (2, 4) has one mine around it, flagging a wrong cell makes the chord dig the mine
+	if _, err := f.Flag(2, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMove(t, f, func() (ChangeSet, error) { return f.Chord(2, 4) })
+	if f.GameStatus() != Lost {
+		t.Fatalf("game status is %v after a wrong chord", f.GameStatus())
+	}
+}
+
+func TestChangeSetOfWin(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	if _, err := f.Dig(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	// a flag on a mine, auto-flagged mines are shown the same
+	if _, err := f.Flag(0, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Dig(2, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	// (3, 5) is the last safe cell
+	checkMove(t, f, func() (ChangeSet, error) { return f.Dig(3, 5) })
+	if f.GameStatus() != Won {
+		t.Fatalf("game status is %v after digging every safe cell", f.GameStatus())
+	}
+}
@@ -0,0 +1,63 @@
+package minesweeper
+
+import (
+	"slices"
+
+	"github.com/HuBeZa/minesweeper/minesweeper/internal/deduce"
+)
+
+// forcedCells finds the cells that are certainly safe and the cells that certainly contain a mine,
+// using only the visible board. Flagged cells are trusted to contain mines. The cells are sorted.
+func forcedCells(cells [][]CellStatus, topology Topology, minesLeft int) (safe, mines []Coordinates) {
+	if len(cells) == 0 {
+		return nil, nil
+	}
+
+	width := len(cells[0])
+	for _, finding := range deduce.Deduce(deductionBoard(cells, topology, minesLeft)) {
+		coord := indexToCoordinates(finding.Cell, width)
+		if finding.Mine {
+			mines = append(mines, coord)
+		} else {
+			safe = append(safe, coord)
+		}
+	}
+	slices.SortFunc(safe, compareCoordinates)
+	slices.SortFunc(mines, compareCoordinates)
+	return safe, mines
+}
+
+// DeductionBoard converts the visible board of the minefield to the board the deduction rules run on.
+// Flagged cells are trusted to contain mines, the mines left are the flags left.
+func DeductionBoard(f Minefield) deduce.Board {
+	return deductionBoard(f.AllCellStatus(), f.Topology(), f.FlagsLeft())
+}
+
+// deductionBoard converts the visible board to the board the deduction rules run on
+func deductionBoard(cells [][]CellStatus, topology Topology, minesLeft int) deduce.Board {
+	height, width := len(cells), len(cells[0])
+	board := deduce.Board{Cells: make([]deduce.Cell, 0, width*height), MinesLeft: minesLeft}
+	for row := range cells {
+		for _, status := range cells[row] {
+			board.Cells = append(board.Cells, deductionCell(status))
+		}
+	}
+
+	var buf [8]int
+	board.Neighbors = func(index int) []int {
+		return topology.neighbors(width, height, index, &buf)
+	}
+	return board
+}
+
+func deductionCell(status CellStatus) deduce.Cell {
+	switch {
+	case status == Undugged:
+		return deduce.Hidden
+	case status == Flagged:
+		return deduce.Mine
+	case status >= MinesAround1 && status <= MinesAround8:
+		return deduce.Cell(status)
+	}
+	return deduce.Open
+}
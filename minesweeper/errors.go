@@ -1,5 +1,7 @@
 package minesweeper
 
+import "fmt"
+
 type GameOverError struct{}
 
 func (*GameOverError) Error() string {
@@ -35,3 +37,73 @@ type AlreadyDuggedError struct{}
 func (*AlreadyDuggedError) Error() string {
 	return "cell is already dugged"
 }
+
+type NotDuggedError struct{}
+
+func (*NotDuggedError) Error() string {
+	return "cell is not dugged"
+}
+
+type ChordUnsatisfiedError struct{}
+
+func (*ChordUnsatisfiedError) Error() string {
+	return "the number of flags around the cell does not match its mines count"
+}
+
+type NoGuessInfeasibleError struct{}
+
+func (*NoGuessInfeasibleError) Error() string {
+	return "could not generate a minefield that is solvable without guessing, try fewer mines"
+}
+
+type NothingToUndoError struct{}
+
+func (*NothingToUndoError) Error() string {
+	return "there is no move to undo"
+}
+
+type NothingToRedoError struct{}
+
+func (*NothingToRedoError) Error() string {
+	return "there is no move to redo"
+}
+
+type LayoutNotPlacedError struct{}
+
+func (*LayoutNotPlacedError) Error() string {
+	return "mines are not placed until the first dig"
+}
+
+type ReplayMismatchError struct {
+	Expected GameStatus
+	Actual   GameStatus
+}
+
+func (e *ReplayMismatchError) Error() string {
+	return fmt.Sprintf("replay ended with game status %v, but the recording ended with %v", e.Actual, e.Expected)
+}
+
+type InvalidLayoutError struct {
+	Line   int
+	Reason string
+}
+
+func (e *InvalidLayoutError) Error() string {
+	return fmt.Sprintf("invalid layout at line %v: %v", e.Line, e.Reason)
+}
+
+type InvalidBoardCodeError struct {
+	Reason string
+}
+
+func (e *InvalidBoardCodeError) Error() string {
+	return fmt.Sprintf("invalid board code: %v", e.Reason)
+}
+
+type InvalidGameStateError struct {
+	Reason string
+}
+
+func (e *InvalidGameStateError) Error() string {
+	return fmt.Sprintf("invalid game state: %v", e.Reason)
+}
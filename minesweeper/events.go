@@ -0,0 +1,210 @@
+package minesweeper
+
+import (
+	"slices"
+	"sync"
+)
+
+type EventType int
+
+const (
+	// CellDugEvent - a cell dug by the player, including the cells dug by a chord
+	CellDugEvent EventType = iota
+	// FloodRevealedEvent - a cell revealed automatically, around a dug cell without mines around it
+	FloodRevealedEvent
+	FlaggedEvent
+	UnflaggedEvent
+	// CellRestoredEvent - a cell changed by undo or redo
+	CellRestoredEvent
+	GameWonEvent
+	GameLostEvent
+	// GameResumedEvent - a finished game is back on after undo
+	GameResumedEvent
+)
+
+func (t EventType) String() string {
+	switch t {
+	case CellDugEvent:
+		return "cell dug"
+	case FloodRevealedEvent:
+		return "flood revealed"
+	case FlaggedEvent:
+		return "flagged"
+	case UnflaggedEvent:
+		return "unflagged"
+	case CellRestoredEvent:
+		return "cell restored"
+	case GameWonEvent:
+		return "game won"
+	case GameLostEvent:
+		return "game lost"
+	case GameResumedEvent:
+		return "game resumed"
+	}
+	return "unknown"
+}
+
+// Event - a change of the minefield. Game events carry the last cell dug by the move that changed the game status.
+type Event struct {
+	Type EventType
+	Cell Coordinates
+	// Status is the status of the cell after the change
+	Status CellStatus
+}
+
+type Listener func(Event)
+
+// Observable notifies listeners of the changes made by each move
+type Observable interface {
+	// Subscribe registers a listener, called synchronously once a move is done, with the events in the order they happened.
+	// The returned function unsubscribes the listener.
+	Subscribe(listener Listener) (unsubscribe func())
+}
+
+type subscription struct {
+	listener Listener
+}
+
+// SubscribeChannel delivers the events of a minefield to a channel with the given buffer size.
+// Moves block while the buffer is full, so the channel must be read by another goroutine.
+// Unsubscribing closes the channel and drops the events not delivered yet. It may be called more than once,
+// and from the reading goroutine while a move waits for the buffer.
+func SubscribeChannel(f Observable, size int) (<-chan Event, func()) {
+	c := &channelListener{
+		events: make(chan Event, size),
+		done:   make(chan struct{}),
+	}
+	unsubscribe := f.Subscribe(c.deliver)
+
+	var once sync.Once
+	return c.events, func() {
+		once.Do(func() {
+			// release a delivery waiting for the buffer first, the move it blocks may hold the lock unsubscribe needs
+			close(c.done)
+			unsubscribe()
+			c.close()
+		})
+	}
+}
+
+// channelListener delivers events to a channel, which is closed while no delivery is in progress
+type channelListener struct {
+	events chan Event
+	// done is closed on unsubscribe
+	done chan struct{}
+	// mu is held by deliveries, a listener unsubscribed during a notification is still called by it
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *channelListener) deliver(e Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.events <- e:
+	case <-c.done:
+	}
+}
+
+func (c *channelListener) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	close(c.events)
+}
+
+func (f *minefield) Subscribe(listener Listener) (unsubscribe func()) {
+	s := &subscription{listener: listener}
+	f.subscriptions = append(f.subscriptions, s)
+
+	return func() {
+		for i, curr := range f.subscriptions {
+			if curr == s {
+				f.subscriptions = append(f.subscriptions[:i:i], f.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (f *minefield) notify(events []Event) {
+	if len(events) == 0 || len(f.subscriptions) == 0 {
+		return
+	}
+
+	// listeners may unsubscribe while being notified
+	subscriptions := f.subscriptions
+	for _, e := range events {
+		for _, s := range subscriptions {
+			s.listener(e)
+		}
+	}
+}
+
+func (f *minefield) newEvent(eventType EventType, coord Coordinates) Event {
+	return Event{Type: eventType, Cell: coord, Status: f.CellStatus(coord.Row, coord.Col)}
+}
+
+// moveEvents returns the events of a move that was just made
+func (f *minefield) moveEvents(m *move) []Event {
+	if len(f.subscriptions) == 0 {
+		return nil
+	}
+
+	events := make([]Event, 0, len(m.changes)+1)
+	for _, change := range m.changes {
+		after := f.cells[change.index]
+		before := after.flipped(change.flipped)
+		var eventType EventType
+		switch {
+		case !before.isDug && after.isDug:
+			eventType = FloodRevealedEvent
+			if slices.Contains(m.dug, f.coordinates(int(change.index))) {
+				eventType = CellDugEvent
+			}
+		case !before.isFlagged && after.isFlagged:
+			eventType = FlaggedEvent
+		case before.isFlagged && !after.isFlagged:
+			eventType = UnflaggedEvent
+		default:
+			continue
+		}
+		events = append(events, f.newEvent(eventType, f.coordinates(int(change.index))))
+	}
+	return append(events, f.statusEvents(m, m.statusBefore)...)
+}
+
+// historyEvents returns the events of a move that was just undone or redone
+func (f *minefield) historyEvents(m *move, statusBefore GameStatus) []Event {
+	if len(f.subscriptions) == 0 {
+		return nil
+	}
+
+	events := make([]Event, 0, len(m.changes)+1)
+	for _, change := range m.changes {
+		events = append(events, f.newEvent(CellRestoredEvent, f.coordinates(int(change.index))))
+	}
+	return append(events, f.statusEvents(m, statusBefore)...)
+}
+
+func (f *minefield) statusEvents(m *move, statusBefore GameStatus) []Event {
+	if f.status == statusBefore {
+		return nil
+	}
+
+	var cell Coordinates
+	if len(m.dug) > 0 {
+		cell = m.dug[len(m.dug)-1]
+	}
+	switch f.status {
+	case Won:
+		return []Event{f.newEvent(GameWonEvent, cell)}
+	case Lost:
+		return []Event{f.newEvent(GameLostEvent, cell)}
+	default:
+		return []Event{f.newEvent(GameResumedEvent, cell)}
+	}
+}
@@ -0,0 +1,230 @@
+package minesweeper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// eventsOf returns the events of a move, in the order the listeners got them
+func eventsOf(t *testing.T, f Minefield, move func() (ChangeSet, error)) []Event {
+	t.Helper()
+	var events []Event
+	unsubscribe := f.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+	defer unsubscribe()
+
+	if _, err := move(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestEvents(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	if events := eventsOf(t, f, func() (ChangeSet, error) { return f.Dig(2, 4) }); !reflect.DeepEqual(events,
+		[]Event{{CellDugEvent, Coordinates{2, 4}, MinesAround1}}) {
+		t.Errorf("dig: events are %v", events)
+	}
+
+	// the dug cell comes first, then the cells revealed by the flood
+	events := eventsOf(t, f, func() (ChangeSet, error) { return f.Dig(0, 0) })
+	if len(events) != 23 || events[0] != (Event{CellDugEvent, Coordinates{0, 0}, NoMinesAround}) {
+		t.Fatalf("flood: events are %v, expected (0, 0) dug and 22 cells revealed", events)
+	}
+	revealed := make(map[Coordinates]bool)
+	for _, e := range events[1:] {
+		if e.Type != FloodRevealedEvent || e.Status != f.CellStatus(e.Cell.Row, e.Cell.Col) || revealed[e.Cell] {
+			t.Errorf("flood: unexpected event %v", e)
+		}
+		revealed[e.Cell] = true
+	}
+
+	steps := []struct {
+		name     string
+		move     func() (ChangeSet, error)
+		expected []Event
+	}{
+		{
+			name:     "flag",
+			move:     func() (ChangeSet, error) { return f.Flag(1, 5) },
+			expected: []Event{{FlaggedEvent, Coordinates{1, 5}, Flagged}},
+		},
+		{
+			name:     "unflag",
+			move:     func() (ChangeSet, error) { return f.ToggleFlag(1, 5) },
+			expected: []Event{{UnflaggedEvent, Coordinates{1, 5}, Undugged}},
+		},
+		{
+			name:     "undo unflag",
+			move:     func() (ChangeSet, error) { return f.Undo() },
+			expected: []Event{{CellRestoredEvent, Coordinates{1, 5}, Flagged}},
+		},
+		{
+			name: "chord to win",
+			move: func() (ChangeSet, error) { return f.Chord(2, 4) },
+			expected: []Event{
+				{CellDugEvent, Coordinates{2, 5}, MinesAround1},
+				{CellDugEvent, Coordinates{3, 5}, MinesAround1},
+				{GameWonEvent, Coordinates{3, 5}, MinesAround1},
+			},
+		},
+		{
+			name: "undo win",
+			move: func() (ChangeSet, error) { return f.Undo() },
+			expected: []Event{
+				{CellRestoredEvent, Coordinates{2, 5}, Undugged},
+				{CellRestoredEvent, Coordinates{3, 5}, Undugged},
+				{GameResumedEvent, Coordinates{3, 5}, Undugged},
+			},
+		},
+		{
+			name: "redo win",
+			move: func() (ChangeSet, error) { return f.Redo() },
+			expected: []Event{
+				{CellRestoredEvent, Coordinates{2, 5}, MinesAround1},
+				{CellRestoredEvent, Coordinates{3, 5}, MinesAround1},
+				{GameWonEvent, Coordinates{3, 5}, MinesAround1},
+			},
+		},
+		{
+			name: "undo again",
+			move: func() (ChangeSet, error) { return f.Undo() },
+			expected: []Event{
+				{CellRestoredEvent, Coordinates{2, 5}, Undugged},
+				{CellRestoredEvent, Coordinates{3, 5}, Undugged},
+				{GameResumedEvent, Coordinates{3, 5}, Undugged},
+			},
+		},
+		{
+			name: "dig a mine",
+			move: func() (ChangeSet, error) { return f.Dig(4, 0) },
+			expected: []Event{
+				{CellDugEvent, Coordinates{4, 0}, Explode},
+				{GameLostEvent, Coordinates{4, 0}, Explode},
+			},
+		},
+	}
+	for _, step := range steps {
+		if events := eventsOf(t, f, step.move); !reflect.DeepEqual(events, step.expected) {
+			t.Errorf("%v: events are %v, expected %v", step.name, events, step.expected)
+		}
+	}
+}
+
+// drain reads a channel until it is closed
+func drain(t *testing.T, events <-chan Event) []Event {
+	t.Helper()
+	var res []Event
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return res
+			}
+			res = append(res, e)
+		case <-timeout:
+			t.Fatal("the channel was not closed")
+		}
+	}
+}
+
+func TestSubscribeChannel(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	events, unsubscribe := SubscribeChannel(f, 100)
+	expected := eventsOf(t, f, func() (ChangeSet, error) { return f.Dig(0, 0) })
+
+	unsubscribe()
+	unsubscribe()
+	if got := drain(t, events); !reflect.DeepEqual(got, expected) {
+		t.Errorf("channel events are %v, expected %v", got, expected)
+	}
+	// moves after unsubscribing are not delivered
+	if _, err := f.Flag(0, 5); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnsubscribeDuringNotify(t *testing.T) {
+	f := newChangesTestMinefield(t)
+
+	// the first listener unsubscribes the channel, which is still called for the events of the flood
+	var unsubscribeChannel func()
+	count := 0
+	unsubscribe := f.Subscribe(func(Event) {
+		count++
+		if count == 2 {
+			unsubscribeChannel()
+		}
+	})
+	defer unsubscribe()
+	events, unsubscribeChannel := SubscribeChannel(f, 100)
+
+	if _, err := f.Dig(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := drain(t, events); len(got) != 1 {
+		t.Errorf("the channel got %v events, expected only the event before unsubscribing", len(got))
+	}
+
+	// a listener unsubscribing itself is called until the notification is over, and not after
+	var self []Event
+	var unsubscribeSelf func()
+	unsubscribeSelf = f.Subscribe(func(e Event) {
+		self = append(self, e)
+		unsubscribeSelf()
+	})
+	if _, err := f.Chord(2, 4); err == nil {
+		t.Fatal("chording without flags should fail")
+	}
+	if _, err := f.Flag(1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Chord(2, 4); err != nil {
+		t.Fatal(err)
+	}
+	if len(self) != 1 || self[0].Type != FlaggedEvent {
+		t.Errorf("the listener got %v after unsubscribing itself", self)
+	}
+}
+
+func TestSubscribeChannelUnsubscribeWhileBlocked(t *testing.T) {
+	f := NewSyncMinefield(newChangesTestMinefield(t))
+	events, unsubscribe := SubscribeChannel(f, 1)
+
+	done := make(chan error)
+	go func() {
+		// the flood has more events than the buffer holds
+		_, err := f.Dig(0, 0)
+		done <- err
+	}()
+
+	// read one event so the move is surely delivering, then unsubscribe while it waits for the buffer
+	<-events
+	unsubscribed := make(chan struct{})
+	go func() {
+		unsubscribe()
+		close(unsubscribed)
+	}()
+	timeout := time.After(time.Second)
+	select {
+	case <-unsubscribed:
+	case <-timeout:
+		t.Fatal("unsubscribing is blocked by the move")
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-timeout:
+		t.Fatal("the move is still blocked after unsubscribing")
+	}
+	drain(t, events)
+
+	if f.CellStatus(3, 3) != NoMinesAround {
+		t.Error("the flood was not completed")
+	}
+}
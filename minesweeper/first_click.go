@@ -0,0 +1,83 @@
+package minesweeper
+
+import (
+	"math/rand"
+)
+
+// FirstClickPolicy decides how the mine layout reacts to the first dig of a game
+type FirstClickPolicy int
+
+const (
+	// FirstClickAny - mines are placed upfront, the first dig may hit a mine
+	FirstClickAny FirstClickPolicy = iota
+	// FirstClickSafe - the first dugged cell never contains a mine
+	FirstClickSafe
+	// FirstClickOpening - the first dugged cell and its surroundings never contain a mine, so the first dig opens an area
+	FirstClickOpening
+	// FirstClickCorner - classic windows behavior, a mine under the first dig is moved to the top-left corner
+	// (or to the first free cell to its right)
+	FirstClickCorner
+)
+
+// layoutFunc places the mines of a minefield once the first dugged cell is known, it is called again after it failed
+type layoutFunc func(first Coordinates) (MineList, error)
+
+func newLayout(width, height int, topology Topology, mineCount int, policy FirstClickPolicy, noGuess *NoGuessLimits, seed int64) layoutFunc {
+	if noGuess != nil {
+		return newNoGuessLayout(width, height, topology, mineCount, *noGuess, seed)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	switch policy {
+	case FirstClickSafe:
+		return func(first Coordinates) (MineList, error) {
+			return placeMinesAround(width, height, mineCount, []Coordinates{first}, rnd), nil
+		}
+	case FirstClickOpening:
+		return func(first Coordinates) (MineList, error) {
+			zone := append(topology.SurroundingCells(width, height, first), first)
+			if width*height-len(zone) < mineCount {
+				// not enough room for an opening, settle for a safe first cell
+				zone = []Coordinates{first}
+			}
+			return placeMinesAround(width, height, mineCount, zone, rnd), nil
+		}
+	case FirstClickCorner:
+		mines := NewMineList(width, height, mineCount)
+		mines.RandomizeWith(rnd, mineCount)
+		return func(first Coordinates) (MineList, error) {
+			return moveMineToCorner(width, height, mines, first), nil
+		}
+	default:
+		return nil
+	}
+}
+
+// placeMinesAround randomizes mines while keeping the excluded cells clear.
+// When there is no room left, mines are placed on the excluded cells as well.
+func placeMinesAround(width, height, mineCount int, excluded []Coordinates, rnd *rand.Rand) MineList {
+	mines := NewMineList(width, height, mineCount)
+	if width*height-len(excluded) >= mineCount {
+		mines.Exclude(excluded...)
+	}
+	mines.RandomizeWith(rnd, mineCount)
+	return mines
+}
+
+func moveMineToCorner(width, height int, mines MineList, first Coordinates) MineList {
+	if !mines.IsMine(first.Row, first.Col) {
+		return mines
+	}
+
+	for index := 0; index < width*height; index++ {
+		coord := indexToCoordinates(index, width)
+		if !mines.IsMine(coord.Row, coord.Col) {
+			mines.Move(first, coord)
+			return mines
+		}
+	}
+
+	// the board is full of mines, nowhere to move to
+	return mines
+}
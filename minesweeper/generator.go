@@ -2,18 +2,38 @@ package minesweeper
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 )
 
-var instance = &generator{}
+// noGuessZoneSize - the first dig of a no-guess minefield opens the cell and its surroundings
+const noGuessZoneSize = 9
+
+var instance = &generator{firstClick: FirstClickSafe}
 
 type Generator interface {
 	Custom(width, height, mineCount int) (Minefield, error)
+	// Beginner, Intermediate and Expert never fail, in no-guess mode their first Dig may fail like the first Dig of Custom
 	Beginner() Minefield
 	Intermediate() Minefield
 	Expert() Minefield
+	// WithFirstClickPolicy returns a generator that creates minefields with the given first click policy
+	WithFirstClickPolicy(policy FirstClickPolicy) Generator
+	// WithSeed returns a generator that creates the same mine layout for the same seed, dimensions and first click
+	WithSeed(seed int64) Generator
+	// WithNoGuess returns a generator that creates minefields which are solvable from the first dig without guessing.
+	// The mines are placed by the first Dig, which fails with NoGuessInfeasibleError when no such minefield is found
+	// within the limits. Another cell can be dug then. Custom only fails on densities that leave no room for the opening.
+	WithNoGuess(limits NoGuessLimits) Generator
+	// WithTopology returns a generator that creates minefields with the given topology
+	WithTopology(topology Topology) Generator
 }
 
 type generator struct {
+	firstClick FirstClickPolicy
+	seed       *int64
+	noGuess    *NoGuessLimits
+	topology   Topology
 }
 
 func GameGenerator() Generator {
@@ -25,32 +45,83 @@ func (g *generator) Custom(width, height, mineCount int) (Minefield, error) {
 		return nil, err
 	}
 
-	mines := NewMineList(width, height, mineCount)
-	mines.Randomize(mineCount)
-	
+	if g.noGuess != nil && width*height-noGuessZoneSize < mineCount {
+		return nil, &NoGuessInfeasibleError{}
+	}
 
-	return NewMinefield(width, height, mines), nil
+	seed := g.nextSeed()
+	var f *minefield
+	if layout := newLayout(width, height, g.topology, mineCount, g.firstClick, g.noGuess, seed); layout != nil {
+		f = newDeferredMinefield(width, height, g.topology, mineCount, layout)
+	} else {
+		mines := NewMineList(width, height, mineCount)
+		mines.RandomizeWith(rand.New(rand.NewSource(seed)), mineCount)
+		f = newMinefield(width, height, g.topology, mines)
+	}
+
+	f.seed = seed
+	f.firstClick = g.firstClick
+	f.noGuess = g.noGuess
+	return f, nil
 }
 
 func (g *generator) Beginner() Minefield {
-	f, _ := g.Custom(9, 9, 10)
-	return f
+	return g.preset(9, 9, 10)
 }
 
 func (g *generator) Intermediate() Minefield {
-	f, _ := g.Custom(16, 16, 40)
-	return f
+	return g.preset(16, 16, 40)
 }
 
 func (g *generator) Expert() Minefield {
-	f, _ := g.Custom(30, 16, 99)
+	return g.preset(30, 16, 99)
+}
+
+func (g *generator) preset(width, height, mineCount int) Minefield {
+	// presets are always valid, and leave room for a no-guess opening
+	f, _ := g.Custom(width, height, mineCount)
 	return f
 }
 
+func (g *generator) WithFirstClickPolicy(policy FirstClickPolicy) Generator {
+	clone := *g
+	clone.firstClick = policy
+	return &clone
+}
+
+func (g *generator) WithSeed(seed int64) Generator {
+	clone := *g
+	clone.seed = &seed
+	return &clone
+}
+
+func (g *generator) WithNoGuess(limits NoGuessLimits) Generator {
+	clone := *g
+	clone.noGuess = &limits
+	return &clone
+}
+
+func (g *generator) WithTopology(topology Topology) Generator {
+	clone := *g
+	clone.topology = topology
+	return &clone
+}
+
+func (g *generator) nextSeed() int64 {
+	if g.seed != nil {
+		return *g.seed
+	}
+	return rand.Int63()
+}
+
 func (g *generator) validate(width, height, mineCount int) error {
 	if width < 2 || height < 2 {
 		return fmt.Errorf("minefield dimensions should be at least 2x2")
 	}
+	if width > math.MaxInt32/height {
+		// the history keeps cell indexes as int32
+		return fmt.Errorf("a minefield cannot have more than %v cells", math.MaxInt32)
+	}
 	if mineCount < 1 {
 		return fmt.Errorf("a minefield should have at least one mine")
 	}
@@ -0,0 +1,148 @@
+package minesweeper
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+const generatorTestSeeds = 200
+
+func TestFirstClickPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy FirstClickPolicy
+		// check verifies the status of the first dug cell
+		check func(status CellStatus) bool
+	}{
+		{"safe", FirstClickSafe, func(status CellStatus) bool { return status != Explode }},
+		{"opening", FirstClickOpening, func(status CellStatus) bool { return status == NoMinesAround }},
+		{"corner", FirstClickCorner, func(status CellStatus) bool { return status != Explode }},
+	}
+	for _, test := range tests {
+		for _, topology := range topologies {
+			t.Run(test.name+"/"+topology.String(), func(t *testing.T) {
+				rnd := rand.New(rand.NewSource(1))
+				for seed := int64(0); seed < generatorTestSeeds; seed++ {
+					f, err := GameGenerator().WithSeed(seed).WithFirstClickPolicy(test.policy).WithTopology(topology).
+						Custom(9, 9, 30)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					row, col := rnd.Intn(9), rnd.Intn(9)
+					if _, err := f.Dig(row, col); err != nil {
+						t.Fatalf("seed %v: %v", seed, err)
+					}
+					if status := f.CellStatus(row, col); !test.check(status) || f.GameStatus() == Lost {
+						t.Fatalf("seed %v: first dig at (%v, %v) is %v", seed, row, col, status)
+					}
+					if mines := len(f.State().Mines); mines != 30 {
+						t.Fatalf("seed %v: %v mines were placed, expected 30", seed, mines)
+					}
+				}
+			})
+		}
+	}
+}
+
+// minesOf returns the mines of a minefield, sorted
+func minesOf(f Minefield) []Coordinates {
+	return slices.SortedFunc(slices.Values(f.State().Mines), compareCoordinates)
+}
+
+// layoutOf digs the first click of a generated 16x16 minefield and returns its mines
+func layoutOf(t *testing.T, generator Generator, first Coordinates) []Coordinates {
+	t.Helper()
+	return layoutOfSize(t, generator, 16, 16, 40, first)
+}
+
+func layoutOfSize(t *testing.T, generator Generator, width, height, mineCount int, first Coordinates) []Coordinates {
+	t.Helper()
+	f, err := generator.Custom(width, height, mineCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Dig(first.Row, first.Col); err != nil {
+		t.Fatal(err)
+	}
+	return minesOf(f)
+}
+
+func TestSeededLayouts(t *testing.T) {
+	policies := []FirstClickPolicy{FirstClickAny, FirstClickSafe, FirstClickOpening, FirstClickCorner}
+	for _, policy := range policies {
+		for _, topology := range topologies {
+			generator := GameGenerator().WithFirstClickPolicy(policy).WithTopology(topology)
+			first := Coordinates{Row: 5, Col: 7}
+
+			for seed := int64(0); seed < 20; seed++ {
+				layout := layoutOf(t, generator.WithSeed(seed), first)
+				if again := layoutOf(t, generator.WithSeed(seed), first); !slices.Equal(layout, again) {
+					t.Fatalf("policy %v, %v, seed %v: the same seed and first click gave different layouts",
+						policy, topology, seed)
+				}
+				if other := layoutOf(t, generator.WithSeed(seed+1000), first); slices.Equal(layout, other) {
+					t.Fatalf("policy %v, %v, seeds %v and %v gave the same layout", policy, topology, seed, seed+1000)
+				}
+			}
+		}
+	}
+}
+
+func TestSeededNoGuessLayouts(t *testing.T) {
+	generator := GameGenerator().WithNoGuess(DefaultNoGuessLimits)
+	first := Coordinates{Row: 8, Col: 8}
+	for seed := int64(0); seed < 5; seed++ {
+		layout := layoutOf(t, generator.WithSeed(seed), first)
+		if again := layoutOf(t, generator.WithSeed(seed), first); !slices.Equal(layout, again) {
+			t.Fatalf("seed %v: the same seed and first click gave different no-guess layouts", seed)
+		}
+	}
+}
+
+func TestNoGuessFirstDigRetry(t *testing.T) {
+	// a single attempt per dig, so some first digs fail
+	generator := GameGenerator().WithNoGuess(NoGuessLimits{MaxAttempts: 1, Timeout: time.Minute})
+	retried := false
+	for seed := int64(0); seed < 20; seed++ {
+		f, err := generator.WithSeed(seed).Custom(9, 9, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var first Coordinates
+		for index := 0; index < 81; index++ {
+			first = indexToCoordinates(index, 9)
+			if _, err = f.Dig(first.Row, first.Col); err == nil {
+				break
+			}
+			var infeasible *NoGuessInfeasibleError
+			if !errors.As(err, &infeasible) {
+				t.Fatalf("seed %v: %v", seed, err)
+			}
+			retried = true
+		}
+		if err != nil {
+			continue
+		}
+
+		// the layout doesn't depend on the digs that failed before
+		if fresh := layoutOfSize(t, generator.WithSeed(seed), 9, 9, 20, first); !slices.Equal(fresh, minesOf(f)) {
+			t.Fatalf("seed %v: digging (%v, %v) after failed digs gave another layout", seed, first.Row, first.Col)
+		}
+	}
+	if !retried {
+		t.Fatal("no first dig failed, the test didn't retry")
+	}
+}
+
+func TestNoGuessInfeasibleDensity(t *testing.T) {
+	_, err := GameGenerator().WithNoGuess(DefaultNoGuessLimits).Custom(9, 9, 73)
+	var infeasible *NoGuessInfeasibleError
+	if !errors.As(err, &infeasible) {
+		t.Errorf("expected a no-guess infeasible error, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package minesweeper
+
+import (
+	"fmt"
+
+	"github.com/HuBeZa/minesweeper/minesweeper/internal/deduce"
+)
+
+type HintKind int
+
+const (
+	// SafeHint - the cell certainly doesn't contain a mine
+	SafeHint HintKind = iota
+	// MineHint - the cell certainly contains a mine
+	MineHint
+	// GuessHint - no cell is certain, the cell is the lowest risk guess
+	GuessHint
+)
+
+type Hint struct {
+	Cell Coordinates
+	Kind HintKind
+	// MineProbability is 0 for safe hints and 1 for mine hints. For guesses it is an estimate - the highest ratio of
+	// missing mines to undugged cells among the numbers around the cell - unless Exact is set.
+	MineProbability float64
+	// Exact is set when MineProbability is the mine probability of the cell, see probability.GetHint
+	Exact bool
+}
+
+func (h Hint) String() string {
+	switch h.Kind {
+	case SafeHint:
+		return fmt.Sprintf("(%v, %v) is safe", h.Cell.Row, h.Cell.Col)
+	case MineHint:
+		return fmt.Sprintf("(%v, %v) is a mine", h.Cell.Row, h.Cell.Col)
+	default:
+		chance := "estimated mine chance"
+		if h.Exact {
+			chance = "mine chance"
+		}
+		return fmt.Sprintf("no safe cell, best guess is (%v, %v) with %.0f%% %v", h.Cell.Row, h.Cell.Col, h.MineProbability*100, chance)
+	}
+}
+
+// GetHint suggests the next move, using only the information visible through AllCellStatus.
+// Flags are the player's guesses, so they are ignored. Mine hints are given only for unflagged cells.
+// Guesses are estimated, probability.GetHint picks them by their mine probability.
+func GetHint(f Minefield) (Hint, error) {
+	if f.GameStatus() != GameOn {
+		return Hint{}, &GameOverError{}
+	}
+
+	cells, flagged, minesLeft := unflaggedView(f)
+	safe, mines := forcedCells(cells, f.Topology(), minesLeft)
+	if len(safe) > 0 {
+		return Hint{Cell: safe[0], Kind: SafeHint, Exact: true}, nil
+	}
+	for _, coord := range mines {
+		if _, isFlagged := flagged[coord]; !isFlagged {
+			return Hint{Cell: coord, Kind: MineHint, MineProbability: 1, Exact: true}, nil
+		}
+	}
+
+	return lowestRiskGuess(cells, f.Topology(), flagged, minesLeft), nil
+}
+
+// unflaggedView returns the board with flags removed, the removed flags, and the number of mines hidden in the board
+func unflaggedView(f Minefield) ([][]CellStatus, map[Coordinates]struct{}, int) {
+	cells := f.AllCellStatus()
+	flagged := make(map[Coordinates]struct{})
+	for row := range cells {
+		for col := range cells[row] {
+			if cells[row][col] == Flagged {
+				cells[row][col] = Undugged
+				flagged[Coordinates{Row: row, Col: col}] = struct{}{}
+			}
+		}
+	}
+	return cells, flagged, f.FlagsLeft() + len(flagged)
+}
+
+// lowestRiskGuess estimates the mine probability of each undugged and unflagged cell and returns the lowest one.
+// Cells around numbers get the highest ratio of missing mines to undugged cells among their numbers,
+// other cells get the ratio of the mines left to the undugged cells.
+func lowestRiskGuess(cells [][]CellStatus, topology Topology, flagged map[Coordinates]struct{}, minesLeft int) Hint {
+	width := len(cells[0])
+	constraints, undugged := deduce.Constraints(deductionBoard(cells, topology, minesLeft))
+
+	risk := make(map[int]float64, len(undugged))
+	for _, c := range constraints {
+		ratio := float64(c.Mines) / float64(len(c.Cells))
+		for _, index := range c.Cells {
+			risk[index] = max(risk[index], ratio)
+		}
+	}
+
+	density := float64(minesLeft) / float64(max(len(undugged), 1))
+	best := Hint{Kind: GuessHint, MineProbability: 2}
+	for _, index := range undugged {
+		coord := indexToCoordinates(index, width)
+		if _, isFlagged := flagged[coord]; isFlagged {
+			continue
+		}
+		p, isFrontier := risk[index]
+		if !isFrontier {
+			p = density
+		}
+		if p < best.MineProbability {
+			best.Cell = coord
+			best.MineProbability = p
+		}
+	}
+	return best
+}
@@ -0,0 +1,167 @@
+package minesweeper
+
+// History allows taking back moves and replaying them.
+// Every move of the game is kept, at the cost of 8 bytes for each cell it changed.
+type History interface {
+	// Undo reverts the last move, including its auto-dugged cells and game over, and returns the changed cells
+	Undo() (ChangeSet, error)
+	// Redo replays the last undone move and returns the changed cells
+	Redo() (ChangeSet, error)
+	CanUndo() bool
+	CanRedo() bool
+}
+
+// cellState holds the dug and flagged states of a cell, the only states a move changes
+type cellState uint8
+
+const (
+	dugState cellState = 1 << iota
+	flaggedState
+)
+
+func (c cell) state() cellState {
+	var s cellState
+	if c.isDug {
+		s |= dugState
+	}
+	if c.isFlagged {
+		s |= flaggedState
+	}
+	return s
+}
+
+// flipped returns the cell with the given states flipped
+func (c cell) flipped(s cellState) cell {
+	c.isDug = c.isDug != (s&dugState != 0)
+	c.isFlagged = c.isFlagged != (s&flaggedState != 0)
+	return c
+}
+
+// cellFlip - a cell changed by a move. Flipping its states again undoes the move, and flipping them once more redoes it,
+// so a move keeps 8 bytes per changed cell, whatever the size of the cascade.
+type cellFlip struct {
+	index int32
+	// flipped are the states changed by the move, while the move is being recorded they are the states before it
+	flipped cellState
+}
+
+// move holds everything a single call to a mutating method changed
+type move struct {
+	changes      []cellFlip
+	statusBefore GameStatus
+	statusAfter  GameStatus
+	dugBefore    int
+	dugAfter     int
+	// dug are the cells dug by the player, the rest of the dug cells were revealed automatically
+	dug []Coordinates
+}
+
+// beginMove starts recording the changes of a move.
+// Mines placed by the first dig are kept when it is undone, so the game continues on the same layout.
+func (f *minefield) beginMove() {
+	f.current = &move{
+		statusBefore: f.status,
+		dugBefore:    f.dugCount,
+	}
+}
+
+// endMove stops recording the current move, keeps it in the history and notifies the listeners.
+// It returns the move, or nil if the move failed and changed nothing.
+func (f *minefield) endMove() *move {
+	m := f.current
+	f.current = nil
+	f.markTouched(m, false)
+	if len(m.changes) == 0 {
+		// failed move, nothing to remember
+		return nil
+	}
+
+	for i := range m.changes {
+		m.changes[i].flipped ^= f.cells[m.changes[i].index].state()
+	}
+	m.statusAfter = f.status
+	m.dugAfter = f.dugCount
+
+	f.undoStack = append(f.undoStack, m)
+	f.redoStack = nil
+	f.notify(f.moveEvents(m))
+	return m
+}
+
+// touch remembers the states of a cell before it is changed by the current move
+func (f *minefield) touch(index int) {
+	if f.current == nil || f.touched[index] {
+		return
+	}
+
+	f.touched[index] = true
+	f.current.changes = append(f.current.changes, cellFlip{
+		index:   int32(index),
+		flipped: f.cells[index].state(),
+	})
+}
+
+// markTouched sets or clears the touched mark of the cells changed by a move
+func (f *minefield) markTouched(m *move, touched bool) {
+	for _, change := range m.changes {
+		f.touched[change.index] = touched
+	}
+}
+
+func (f *minefield) Undo() (ChangeSet, error) {
+	if !f.CanUndo() {
+		return nil, &NothingToUndoError{}
+	}
+
+	m := f.undoStack[len(f.undoStack)-1]
+	f.undoStack = f.undoStack[:len(f.undoStack)-1]
+
+	for _, change := range m.changes {
+		f.flipCell(int(change.index), change.flipped)
+	}
+	f.status = m.statusBefore
+	f.dugCount = m.dugBefore
+
+	f.redoStack = append(f.redoStack, m)
+	f.notify(f.historyEvents(m, m.statusAfter))
+	return f.changeSet(m, true), nil
+}
+
+func (f *minefield) Redo() (ChangeSet, error) {
+	if !f.CanRedo() {
+		return nil, &NothingToRedoError{}
+	}
+
+	m := f.redoStack[len(f.redoStack)-1]
+	f.redoStack = f.redoStack[:len(f.redoStack)-1]
+
+	for _, change := range m.changes {
+		f.flipCell(int(change.index), change.flipped)
+	}
+	f.status = m.statusAfter
+	f.dugCount = m.dugAfter
+
+	f.undoStack = append(f.undoStack, m)
+	f.notify(f.historyEvents(m, m.statusBefore))
+	return f.changeSet(m, false), nil
+}
+
+func (f *minefield) CanUndo() bool {
+	return len(f.undoStack) > 0
+}
+
+func (f *minefield) CanRedo() bool {
+	return len(f.redoStack) > 0
+}
+
+// flipCell flips the dug and flagged states of a cell, the mines layout is not part of the history
+func (f *minefield) flipCell(index int, flipped cellState) {
+	coord := f.coordinates(index)
+	c := &f.cells[index]
+	*c = c.flipped(flipped)
+	if c.isFlagged {
+		f.flags[coord] = struct{}{}
+	} else {
+		delete(f.flags, coord)
+	}
+}
@@ -0,0 +1,187 @@
+// Package deduce holds the deduction rules shared by the hints, the no-guess generator and the solver,
+// so they all agree on which cells are forced and on which boards can be solved without guessing.
+package deduce
+
+import (
+	"slices"
+)
+
+// Cell - the visible state of a cell, as the rules see it. Dug numbered cells hold the mines count around them.
+type Cell int8
+
+const (
+	// Hidden - an undugged cell that is not flagged
+	Hidden Cell = -1
+	// Mine - a flagged cell, trusted to be a mine
+	Mine Cell = -2
+	// Open - a dug cell that takes no part in the rules, like a cell without mines around it
+	Open Cell = -3
+)
+
+type Rule int
+
+const (
+	// SingleCell - a number whose missing mines are zero, or equal to its undugged surroundings
+	SingleCell Rule = iota
+	// Subset - a number whose undugged surroundings contain the undugged surroundings of another number,
+	// so the cells only it touches hold the difference between their missing mines
+	Subset
+	// GlobalCount - the mines left are zero, or equal to the undugged cells
+	GlobalCount
+)
+
+func (r Rule) String() string {
+	switch r {
+	case SingleCell:
+		return "single cell"
+	case Subset:
+		return "subset"
+	case GlobalCount:
+		return "global count"
+	}
+	return "unknown"
+}
+
+// Board - the visible state of a minefield, with the cells in row-major order
+type Board struct {
+	Cells []Cell
+	// Neighbors returns the indexes of the cells around a cell. The slice is only read until the next call.
+	Neighbors func(index int) []int
+	// MinesLeft is the number of mines hidden in the undugged cells that are not flagged
+	MinesLeft int
+}
+
+// Constraint - the missing mines of a numbered cell, spread over its hidden surroundings
+type Constraint struct {
+	Source int
+	// Cells are sorted
+	Cells []int
+	Mines int
+}
+
+// Finding - a cell that is certainly safe or certainly a mine, with the rule that found it
+type Finding struct {
+	Cell int
+	Mine bool
+	Rule Rule
+	// Sources are the numbered cells the finding is based on, the inner number first for Subset, none for GlobalCount
+	Sources []int
+}
+
+// Constraints returns the constraints of all numbered cells bordering hidden cells, and all hidden cells.
+// Constraints over the same cells add nothing, only the first of them is kept.
+func Constraints(b Board) ([]Constraint, []int) {
+	constraints := make([]Constraint, 0)
+	hidden := make([]int, 0)
+	seen := make(map[string]struct{})
+
+	for index, cell := range b.Cells {
+		if cell == Hidden {
+			hidden = append(hidden, index)
+			continue
+		}
+		if cell <= 0 {
+			continue
+		}
+
+		c := Constraint{Source: index, Mines: int(cell)}
+		for _, n := range b.Neighbors(index) {
+			switch b.Cells[n] {
+			case Hidden:
+				c.Cells = append(c.Cells, n)
+			case Mine:
+				c.Mines--
+			}
+		}
+		if len(c.Cells) == 0 {
+			continue
+		}
+
+		slices.Sort(c.Cells)
+		key := constraintKey(c.Cells)
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		constraints = append(constraints, c)
+	}
+	return constraints, hidden
+}
+
+// Deduce applies each rule once and returns the forced cells, each cell once with the first rule that found it.
+// Findings are not fed back into the board. Playing them reveals more than the rules could assume, so callers play
+// them and deduce again, until nothing is found and the next move is a guess.
+func Deduce(b Board) []Finding {
+	constraints, hidden := Constraints(b)
+	found := make(map[int]struct{})
+	res := make([]Finding, 0)
+	add := func(cells []int, mine bool, rule Rule, sources ...int) {
+		for _, cell := range cells {
+			if _, exists := found[cell]; exists {
+				continue
+			}
+			found[cell] = struct{}{}
+			res = append(res, Finding{Cell: cell, Mine: mine, Rule: rule, Sources: sources})
+		}
+	}
+
+	for _, c := range constraints {
+		if c.Mines == 0 {
+			add(c.Cells, false, SingleCell, c.Source)
+		} else if c.Mines == len(c.Cells) {
+			add(c.Cells, true, SingleCell, c.Source)
+		}
+	}
+
+	// if A's cells are a subset of B's cells, B's other cells hold exactly B-A mines
+	for i, a := range constraints {
+		for j, b := range constraints {
+			if i == j || len(a.Cells) >= len(b.Cells) || !isSubset(a.Cells, b.Cells) {
+				continue
+			}
+
+			rest := difference(b.Cells, a.Cells)
+			restMines := b.Mines - a.Mines
+			if restMines == 0 {
+				add(rest, false, Subset, a.Source, b.Source)
+			} else if restMines == len(rest) {
+				add(rest, true, Subset, a.Source, b.Source)
+			}
+		}
+	}
+
+	if b.MinesLeft == 0 {
+		add(hidden, false, GlobalCount)
+	} else if b.MinesLeft == len(hidden) {
+		add(hidden, true, GlobalCount)
+	}
+
+	return res
+}
+
+func constraintKey(cells []int) string {
+	key := make([]byte, 0, len(cells)*4)
+	for _, c := range cells {
+		key = append(key, byte(c), byte(c>>8), byte(c>>16), byte(c>>24))
+	}
+	return string(key)
+}
+
+func isSubset(sub, set []int) bool {
+	for _, c := range sub {
+		if _, found := slices.BinarySearch(set, c); !found {
+			return false
+		}
+	}
+	return true
+}
+
+func difference(set, sub []int) []int {
+	res := make([]int, 0, len(set)-len(sub))
+	for _, c := range set {
+		if _, found := slices.BinarySearch(sub, c); !found {
+			res = append(res, c)
+		}
+	}
+	return res
+}
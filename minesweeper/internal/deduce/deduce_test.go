@@ -0,0 +1,85 @@
+package deduce
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lineBoard is a single row of cells, each touching the cells next to it
+func lineBoard(minesLeft int, cells ...Cell) Board {
+	return Board{
+		Cells:     cells,
+		MinesLeft: minesLeft,
+		Neighbors: func(index int) []int {
+			var res []int
+			if index > 0 {
+				res = append(res, index-1)
+			}
+			if index < len(cells)-1 {
+				res = append(res, index+1)
+			}
+			return res
+		},
+	}
+}
+
+func TestDeduce(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    Board
+		expected []Finding
+	}{
+		{
+			name:     "number satisfied by a flag",
+			board:    lineBoard(0, Mine, 1, Hidden),
+			expected: []Finding{{Cell: 2, Mine: false, Rule: SingleCell, Sources: []int{1}}},
+		},
+		{
+			name:     "number filled by its hidden cells",
+			board:    lineBoard(5, Open, 1, Hidden, Hidden),
+			expected: []Finding{{Cell: 2, Mine: true, Rule: SingleCell, Sources: []int{1}}},
+		},
+		{
+			name:  "subset of another number",
+			board: lineBoard(5, Hidden, 1, Hidden, 1, Open),
+			// 1 at 3 has its mine at 2, so the 1 at 1 has no mine at 0
+			expected: []Finding{
+				{Cell: 2, Mine: true, Rule: SingleCell, Sources: []int{3}},
+				{Cell: 0, Mine: false, Rule: Subset, Sources: []int{3, 1}},
+			},
+		},
+		{
+			name:     "all mines found",
+			board:    lineBoard(0, Hidden, Open, Hidden),
+			expected: []Finding{{Cell: 0, Rule: GlobalCount}, {Cell: 2, Rule: GlobalCount}},
+		},
+		{
+			name:     "mines fill the hidden cells",
+			board:    lineBoard(2, Hidden, Open, Hidden),
+			expected: []Finding{{Cell: 0, Mine: true, Rule: GlobalCount}, {Cell: 2, Mine: true, Rule: GlobalCount}},
+		},
+		{
+			name:     "a guess is needed",
+			board:    lineBoard(1, Hidden, 1, Hidden),
+			expected: []Finding{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if res := Deduce(test.board); !reflect.DeepEqual(res, test.expected) {
+				t.Fatalf("expected %+v, got %+v", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestConstraintsKeepOneOfIdenticalConstraints(t *testing.T) {
+	// both numbers touch only the hidden cell between them
+	constraints, hidden := Constraints(lineBoard(1, 1, Hidden, 1))
+	if len(constraints) != 1 || constraints[0].Source != 0 {
+		t.Fatalf("expected the constraint of the first number only, got %+v", constraints)
+	}
+	if !reflect.DeepEqual(hidden, []int{1}) {
+		t.Fatalf("expected hidden cell 1, got %v", hidden)
+	}
+}
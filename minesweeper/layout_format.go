@@ -0,0 +1,293 @@
+package minesweeper
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LayoutFormat - a text format of a mines layout
+type LayoutFormat int
+
+const (
+	// GridLayout - a line per row, '*' for a mine and '.' for an empty cell
+	GridLayout LayoutFormat = iota
+	// CoordinatesLayout - a "<width>x<height>" line, followed by a "<row>,<col>" line per mine
+	CoordinatesLayout
+	// TathamLayout - a game id of Simon Tatham's Mines, e.g. "9x9n10:u000081090010004006030"
+	TathamLayout
+)
+
+func (f LayoutFormat) String() string {
+	switch f {
+	case GridLayout:
+		return "grid"
+	case CoordinatesLayout:
+		return "coordinates"
+	case TathamLayout:
+		return "tatham"
+	}
+	return "unknown"
+}
+
+// Layout - the dimensions and the mines of a minefield.
+// The text formats hold no topology, layouts read from them are flat.
+type Layout struct {
+	Width    int
+	Height   int
+	Mines    MineList
+	Topology Topology
+}
+
+// Minefield creates a new game on the layout
+func (l Layout) Minefield() (Minefield, error) {
+	if err := instance.validate(l.Width, l.Height, l.Mines.Len()); err != nil {
+		return nil, err
+	}
+	return newMinefield(l.Width, l.Height, l.Topology, l.Mines), nil
+}
+
+// LayoutOf returns the layout of a minefield.
+// The layout of a minefield that places its mines on the first dig is unknown until then.
+func LayoutOf(f Minefield) (Layout, error) {
+	state := f.State()
+	if len(state.Mines) == 0 && state.MineCount > 0 {
+		return Layout{}, &LayoutNotPlacedError{}
+	}
+
+	mines := NewMineList(state.Width, state.Height, len(state.Mines))
+	for _, coord := range state.Mines {
+		if err := mines.Add(coord.Row, coord.Col); err != nil {
+			return Layout{}, &InvalidCoordinatesError{}
+		}
+	}
+	return Layout{Width: state.Width, Height: state.Height, Mines: mines, Topology: state.Topology}, nil
+}
+
+var (
+	dimensionsRegexp = regexp.MustCompile(`^(\d+)x(\d+)$`)
+	tathamRegexp     = regexp.MustCompile(`^(\d+)x(\d+)(?:n(\d+))?[a-z]*:(?:\d+,\d+,)?(.*)$`)
+)
+
+// ReadLayout reads a layout in any of the layout formats, telling them apart by their first line.
+// Empty lines, and lines starting with '#' in the grid and coordinates formats, are skipped.
+func ReadLayout(r io.Reader) (Layout, error) {
+	lines, err := readLayoutLines(r)
+	if err != nil {
+		return Layout{}, err
+	}
+	if len(lines) == 0 {
+		return Layout{}, &InvalidLayoutError{Line: 1, Reason: "the layout is empty"}
+	}
+
+	switch first := lines[0].text; {
+	case strings.Contains(first, ":"):
+		return parseTathamLayout(lines)
+	case dimensionsRegexp.MatchString(first):
+		return parseCoordinatesLayout(lines)
+	default:
+		return parseGridLayout(lines)
+	}
+}
+
+type layoutLine struct {
+	number int
+	text   string
+}
+
+func readLayoutLines(r io.Reader) ([]layoutLine, error) {
+	var lines []layoutLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		lines = append(lines, layoutLine{number: number, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read layout: %w", err)
+	}
+	return lines, nil
+}
+
+func parseGridLayout(lines []layoutLine) (Layout, error) {
+	layout := Layout{Width: len(lines[0].text), Height: len(lines)}
+	layout.Mines = NewMineList(layout.Width, layout.Height, 0)
+	for row, line := range lines {
+		if len(line.text) != layout.Width {
+			return Layout{}, &InvalidLayoutError{Line: line.number, Reason: fmt.Sprintf("expected %v cells", layout.Width)}
+		}
+		for col, char := range []byte(line.text) {
+			switch char {
+			case '*':
+				layout.Mines.Add(row, col)
+			case '.':
+			default:
+				return Layout{}, &InvalidLayoutError{Line: line.number, Reason: fmt.Sprintf("unexpected '%c', use '*' or '.'", char)}
+			}
+		}
+	}
+	return layout, nil
+}
+
+func parseCoordinatesLayout(lines []layoutLine) (Layout, error) {
+	match := dimensionsRegexp.FindStringSubmatch(lines[0].text)
+	layout := Layout{}
+	layout.Width, _ = strconv.Atoi(match[1])
+	layout.Height, _ = strconv.Atoi(match[2])
+	layout.Mines = NewMineList(layout.Width, layout.Height, len(lines)-1)
+
+	for _, line := range lines[1:] {
+		rowStr, colStr, found := strings.Cut(line.text, ",")
+		row, rowErr := strconv.Atoi(strings.TrimSpace(rowStr))
+		col, colErr := strconv.Atoi(strings.TrimSpace(colStr))
+		if !found || rowErr != nil || colErr != nil {
+			return Layout{}, &InvalidLayoutError{Line: line.number, Reason: "expected <row>,<col>"}
+		}
+		if layout.Mines.IsMine(row, col) {
+			return Layout{}, &InvalidLayoutError{Line: line.number, Reason: "duplicate mine"}
+		}
+		if err := layout.Mines.Add(row, col); err != nil {
+			return Layout{}, &InvalidLayoutError{Line: line.number, Reason: "mine is outside the minefield"}
+		}
+	}
+	return layout, nil
+}
+
+// parseTathamLayout reads a game id of Simon Tatham's Mines. The description is a hex bitmap of the mines row by row,
+// prefixed by 'u', or by 'm' when the bitmap is obfuscated. Ids of games whose mines are generated on the first click
+// (prefixed by 'r') don't hold the mines and can't be read.
+func parseTathamLayout(lines []layoutLine) (Layout, error) {
+	line := lines[0]
+	if len(lines) > 1 {
+		return Layout{}, &InvalidLayoutError{Line: lines[1].number, Reason: "expected a single game id"}
+	}
+	match := tathamRegexp.FindStringSubmatch(line.text)
+	if match == nil {
+		return Layout{}, &InvalidLayoutError{Line: line.number, Reason: "expected <width>x<height>:<description>"}
+	}
+
+	layout := Layout{}
+	layout.Width, _ = strconv.Atoi(match[1])
+	layout.Height, _ = strconv.Atoi(match[2])
+	desc := match[4]
+	if desc == "" || (desc[0] != 'u' && desc[0] != 'm') {
+		return Layout{}, &InvalidLayoutError{Line: line.number, Reason: "only game ids that list the mines are supported"}
+	}
+
+	area := layout.Width * layout.Height
+	hex := desc[1:]
+	if len(hex) != (area+3)/4 {
+		return Layout{}, &InvalidLayoutError{Line: line.number, Reason: fmt.Sprintf("expected %v hex digits", (area+3)/4)}
+	}
+	bitmap := make([]byte, (area+7)/8)
+	for i := range hex {
+		value, err := strconv.ParseUint(hex[i:i+1], 16, 8)
+		if err != nil {
+			return Layout{}, &InvalidLayoutError{Line: line.number, Reason: fmt.Sprintf("unexpected '%c' in mines bitmap", hex[i])}
+		}
+		if i%2 == 0 {
+			value <<= 4
+		}
+		bitmap[i/2] |= byte(value)
+	}
+	if desc[0] == 'm' {
+		obfuscateBitmap(bitmap, area, true)
+	}
+
+	layout.Mines = NewMineList(layout.Width, layout.Height, 0)
+	for i := 0; i < area; i++ {
+		if bitmap[i/8]&(0x80>>(i%8)) != 0 {
+			coord := indexToCoordinates(i, layout.Width)
+			layout.Mines.Add(coord.Row, coord.Col)
+		}
+	}
+	if match[3] != "" {
+		if mineCount, _ := strconv.Atoi(match[3]); mineCount != layout.Mines.Len() {
+			return Layout{}, &InvalidLayoutError{Line: line.number, Reason: fmt.Sprintf("expected %v mines but found %v", mineCount, layout.Mines.Len())}
+		}
+	}
+	return layout, nil
+}
+
+// obfuscateBitmap is the reversible scrambling Mines applies to the bitmap of 'm' descriptions, so players can't read
+// the mines off the game id. Each half of the bitmap is xored with a SHA-1 based stream seeded by the other half.
+func obfuscateBitmap(bitmap []byte, bits int, decode bool) {
+	firstHalf := len(bitmap) / 2
+	first, second := bitmap[:firstHalf], bitmap[firstHalf:]
+	steps := [][2][]byte{{second, first}, {first, second}}
+	if decode {
+		slices.Reverse(steps)
+	}
+
+	for _, step := range steps {
+		seed, target := step[0], step[1]
+		var digest [sha1.Size]byte
+		for i := range target {
+			if i%sha1.Size == 0 {
+				digest = sha1.Sum(append(slices.Clone(seed), strconv.Itoa(i/sha1.Size)...))
+			}
+			target[i] ^= digest[i%sha1.Size]
+		}
+
+		// clear the padding bits of the last byte
+		if bits%8 != 0 {
+			bitmap[bits/8] &= 0xff << (8 - bits%8)
+		}
+	}
+}
+
+// WriteLayout writes a layout in the given format. Tatham game ids are written with a plain bitmap, which Mines reads
+// as well as its obfuscated ones.
+func WriteLayout(w io.Writer, layout Layout, format LayoutFormat) error {
+	mines := slices.SortedFunc(slices.Values(layout.Mines.Coordinates()), compareCoordinates)
+	var sb strings.Builder
+
+	switch format {
+	case GridLayout:
+		for row := range layout.Height {
+			for col := range layout.Width {
+				if layout.Mines.IsMine(row, col) {
+					sb.WriteByte('*')
+				} else {
+					sb.WriteByte('.')
+				}
+			}
+			sb.WriteByte('\n')
+		}
+	case CoordinatesLayout:
+		fmt.Fprintf(&sb, "%vx%v\n", layout.Width, layout.Height)
+		for _, coord := range mines {
+			fmt.Fprintf(&sb, "%v,%v\n", coord.Row, coord.Col)
+		}
+	case TathamLayout:
+		area := layout.Width * layout.Height
+		bitmap := make([]byte, (area+7)/8)
+		for _, coord := range mines {
+			i := coord.Row*layout.Width + coord.Col
+			bitmap[i/8] |= 0x80 >> (i % 8)
+		}
+
+		fmt.Fprintf(&sb, "%vx%vn%v:u", layout.Width, layout.Height, len(mines))
+		for i := range (area + 3) / 4 {
+			value := bitmap[i/2]
+			if i%2 == 0 {
+				value >>= 4
+			}
+			sb.WriteString(strconv.FormatUint(uint64(value&0xf), 16))
+		}
+		sb.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown layout format %v", format)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,138 @@
+package minesweeper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// gridOf writes the mines of a layout in the grid format, to compare layouts by their text
+func gridOf(t *testing.T, layout Layout) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := WriteLayout(&sb, layout, GridLayout); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+// The obfuscated ids were made with the description code of mines.c, ported as is: the mines bitmap is obfuscated
+// by obfuscate_bitmap and prefixed by the coordinates of the first click.
+var tathamTests = []struct {
+	name string
+	id   string
+	grid string
+}{
+	{
+		name: "plain",
+		id:   "9x9n10:u008140208000804900800",
+		grid: "........*\n......*.*\n........*\n.....*...\n.........\n...*.....\n...*..*..\n*........\n*........\n",
+	},
+	{
+		name: "plain with first click",
+		id:   "9x9n10:0,0,u008140208000804900800",
+		grid: "........*\n......*.*\n........*\n.....*...\n.........\n...*.....\n...*..*..\n*........\n*........\n",
+	},
+	{
+		name: "obfuscated",
+		id:   "9x9n10:0,0,m2e75b7ad3bfff32c8ad80",
+		grid: "........*\n......*.*\n........*\n.....*...\n.........\n...*.....\n...*..*..\n*........\n*........\n",
+	},
+	{
+		// two bytes, the first is obfuscated by the second and the second by the first
+		name: "obfuscated partial byte",
+		id:   "5x3n4:2,0,mb3f4",
+		grid: "**...\n.....\n...**\n",
+	},
+	{
+		name: "without mines count",
+		id:   "5x3:2,0,mb3f4",
+		grid: "**...\n.....\n...**\n",
+	},
+}
+
+func TestReadTathamLayout(t *testing.T) {
+	for _, test := range tathamTests {
+		t.Run(test.name, func(t *testing.T) {
+			layout, err := ReadLayout(strings.NewReader(test.id))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := gridOf(t, layout); got != test.grid {
+				t.Errorf("mines are\n%v\nexpected\n%v", got, test.grid)
+			}
+		})
+	}
+}
+
+func TestReadTathamLayoutErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"not generated yet", "9x9n10:r10,u,ad"},
+		{"missing digits", "9x9n10:u00814020800080490080"},
+		{"bad digit", "9x9n10:u00814020800080490080g"},
+		{"wrong mines count", "9x9n11:0,0,m2e75b7ad3bfff32c8ad80"},
+		{"corrupted obfuscation", "9x9n10:0,0,m3e75b7ad3bfff32c8ad80"},
+		{"two ids", "5x3:2,0,mb3f4\n5x3:2,0,mb3f4"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadLayout(strings.NewReader(test.id))
+			var layoutErr *InvalidLayoutError
+			if !errors.As(err, &layoutErr) {
+				t.Errorf("expected an invalid layout error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteLayoutRoundTrip(t *testing.T) {
+	for _, test := range tathamTests {
+		layout, err := ReadLayout(strings.NewReader(test.id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []LayoutFormat{GridLayout, CoordinatesLayout, TathamLayout} {
+			t.Run(test.name+"/"+format.String(), func(t *testing.T) {
+				var sb strings.Builder
+				if err := WriteLayout(&sb, layout, format); err != nil {
+					t.Fatal(err)
+				}
+				read, err := ReadLayout(strings.NewReader(sb.String()))
+				if err != nil {
+					t.Fatalf("%v: %v", sb.String(), err)
+				}
+				if read.Width != layout.Width || read.Height != layout.Height {
+					t.Errorf("read a %vx%v layout, expected %vx%v", read.Width, read.Height, layout.Width, layout.Height)
+				}
+				if got := gridOf(t, read); got != test.grid {
+					t.Errorf("mines are\n%v\nexpected\n%v", got, test.grid)
+				}
+			})
+		}
+	}
+}
+
+func TestObfuscateBitmap(t *testing.T) {
+	for _, bits := range []int{1, 8, 15, 81, 480} {
+		bitmap := make([]byte, (bits+7)/8)
+		for i := range bitmap {
+			bitmap[i] = byte(i*37 + 11)
+		}
+		if bits%8 != 0 {
+			bitmap[len(bitmap)-1] &= 0xff << (8 - bits%8)
+		}
+		plain := string(bitmap)
+
+		obfuscateBitmap(bitmap, bits, false)
+		if bits >= 8 && string(bitmap) == plain {
+			t.Errorf("%v bits: obfuscating changed nothing", bits)
+		}
+		obfuscateBitmap(bitmap, bits, true)
+		if string(bitmap) != plain {
+			t.Errorf("%v bits: decoding didn't restore the bitmap", bits)
+		}
+	}
+}
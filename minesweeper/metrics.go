@@ -0,0 +1,164 @@
+package minesweeper
+
+import (
+	"fmt"
+)
+
+// Metrics - standard difficulty measures of a mines layout
+type Metrics struct {
+	// BBBV is the 3BV (Bechtel's Board Benchmark Value) - the minimal number of clicks that clears the board,
+	// one for each opening and one for each isolated number
+	BBBV int
+	// Openings are the sizes of the openings - the cells a single dig reveals in an area without mines around,
+	// including the numbers bordering it
+	Openings []int
+	// IsolatedNumbers are the numbered cells that don't border any opening, each has to be dug on its own
+	IsolatedNumbers int
+	// Islands are the connected groups of isolated numbers
+	Islands int
+	Density float64
+}
+
+func (m Metrics) String() string {
+	return fmt.Sprintf("3BV: %v • openings: %v • islands: %v • density: %.1f%%",
+		m.BBBV, len(m.Openings), m.Islands, m.Density*100)
+}
+
+// AnalyzeMines computes the metrics of a mines layout on a flat minefield
+func AnalyzeMines(width, height int, mines MineList) (Metrics, error) {
+	return AnalyzeLayout(Layout{Width: width, Height: height, Mines: mines})
+}
+
+// AnalyzeLayout computes the metrics of a mines layout
+func AnalyzeLayout(layout Layout) (Metrics, error) {
+	if layout.Width <= 0 || layout.Height <= 0 {
+		return Metrics{}, &InvalidCoordinatesError{}
+	}
+
+	f := newEmptyMinefield(layout.Width, layout.Height, layout.Topology, layout.Mines.Len())
+	for _, coord := range layout.Mines.Coordinates() {
+		if valid, _ := f.getCell(coord.Row, coord.Col); !valid {
+			return Metrics{}, &InvalidCoordinatesError{}
+		}
+	}
+	f.placeMines(layout.Mines)
+	return f.metrics(), nil
+}
+
+// AnalyzeMinefield computes the metrics of the minefield's mines layout.
+// The layout of a minefield that places its mines on the first dig is unknown until then.
+func AnalyzeMinefield(f Minefield) (Metrics, error) {
+	layout, err := LayoutOf(f)
+	if err != nil {
+		return Metrics{}, err
+	}
+	return AnalyzeLayout(layout)
+}
+
+func (f *minefield) metrics() Metrics {
+	m := Metrics{
+		Openings: make([]int, 0),
+		Density:  float64(f.mineCount) / float64(f.width*f.height),
+	}
+
+	// flood each opening from its first zero cell, marking the zeros and the numbers bordering them
+	inOpening := make([]bool, len(f.cells))
+	for i, c := range f.cells {
+		if c.isMine || c.minesAround > 0 || inOpening[i] {
+			continue
+		}
+		size := f.floodOpening(i, inOpening)
+		m.Openings = append(m.Openings, size)
+	}
+
+	// isolated numbers, grouped to islands by adjacency
+	isolated := make([]bool, len(f.cells))
+	for i, c := range f.cells {
+		if !inOpening[i] && !c.isMine {
+			isolated[i] = true
+			m.IsolatedNumbers++
+		}
+	}
+
+	var buf [8]int
+	visited := make([]bool, len(f.cells))
+	for i := range f.cells {
+		if !isolated[i] || visited[i] {
+			continue
+		}
+		m.Islands++
+
+		visited[i] = true
+		stack := []int{i}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, n := range f.neighbors(current, &buf) {
+				if isolated[n] && !visited[n] {
+					visited[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+	}
+
+	m.BBBV = len(m.Openings) + m.IsolatedNumbers
+	return m
+}
+
+// bbbvCells returns the cells whose digging does a 3BV click - the first zero cell of each opening,
+// as digging any of its zeros reveals all of it, and the isolated numbers
+func (f *minefield) bbbvCells() []int {
+	res := make([]int, 0)
+	inOpening := make([]bool, len(f.cells))
+	for i, c := range f.cells {
+		if c.isMine || c.minesAround > 0 || inOpening[i] {
+			continue
+		}
+		f.floodOpening(i, inOpening)
+		res = append(res, i)
+	}
+
+	for i, c := range f.cells {
+		if !inOpening[i] && !c.isMine {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// solvedBBBV counts the 3BV clicks already done - the openings and the isolated numbers that were dug
+func (f *minefield) solvedBBBV() int {
+	solved := 0
+	for _, i := range f.bbbvCells() {
+		if f.cells[i].isDug {
+			solved++
+		}
+	}
+	return solved
+}
+
+// floodOpening marks the cells revealed by digging a zero cell, and returns how many of them were not marked yet
+func (f *minefield) floodOpening(start int, inOpening []bool) int {
+	size := 1
+	inOpening[start] = true
+	stack := []int{start}
+	var buf [8]int
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, n := range f.neighbors(current, &buf) {
+			if inOpening[n] {
+				continue
+			}
+			// numbers bordering two openings are counted in the first
+			inOpening[n] = true
+			size++
+			if f.cells[n].minesAround == 0 {
+				stack = append(stack, n)
+			}
+		}
+	}
+	return size
+}
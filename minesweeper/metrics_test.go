@@ -0,0 +1,125 @@
+package minesweeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+// columnOfMines is a 4x4 layout with its second column full of mines. On a flat board the first column is an
+// island of numbers beside the opening, on a torus it borders the opening through the edge.
+func columnOfMines(topology Topology) Layout {
+	mines := NewMineList(4, 4, 4)
+	for row := 0; row < 4; row++ {
+		mines.Add(row, 1)
+	}
+	return Layout{Width: 4, Height: 4, Topology: topology, Mines: mines}
+}
+
+func layoutWithMines(width, height int, topology Topology, coords ...Coordinates) Layout {
+	mines := NewMineList(width, height, len(coords))
+	for _, coord := range coords {
+		mines.Add(coord.Row, coord.Col)
+	}
+	return Layout{Width: width, Height: height, Topology: topology, Mines: mines}
+}
+
+func TestAnalyzeLayout(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   Layout
+		expected Metrics
+	}{
+		{
+			name: "one opening",
+			// the 3 numbers around the mine border the opening
+			layout:   layoutWithMines(4, 4, FlatTopology, Coordinates{0, 0}),
+			expected: Metrics{BBBV: 1, Openings: []int{15}, Density: 1.0 / 16},
+		},
+		{
+			name: "isolated numbers",
+			// mines in the corners, the numbers between them are all connected through the center
+			layout: layoutWithMines(3, 3, FlatTopology,
+				Coordinates{0, 0}, Coordinates{0, 2}, Coordinates{2, 0}, Coordinates{2, 2}),
+			expected: Metrics{BBBV: 5, Openings: []int{}, IsolatedNumbers: 5, Islands: 1, Density: 4.0 / 9},
+		},
+		{
+			name: "several islands",
+			// columns of mines split the numbers to two islands
+			layout: layoutWithMines(5, 2, FlatTopology,
+				Coordinates{0, 0}, Coordinates{1, 0}, Coordinates{0, 2}, Coordinates{1, 2},
+				Coordinates{0, 4}, Coordinates{1, 4}),
+			expected: Metrics{BBBV: 4, Openings: []int{}, IsolatedNumbers: 4, Islands: 2, Density: 6.0 / 10},
+		},
+		{
+			name: "two openings",
+			layout: layoutWithMines(7, 3, FlatTopology,
+				Coordinates{0, 3}, Coordinates{1, 3}, Coordinates{2, 3}),
+			expected: Metrics{BBBV: 2, Openings: []int{9, 9}, Density: 3.0 / 21},
+		},
+		{
+			name:     "opening and island",
+			layout:   columnOfMines(FlatTopology),
+			expected: Metrics{BBBV: 5, Openings: []int{8}, IsolatedNumbers: 4, Islands: 1, Density: 4.0 / 16},
+		},
+		{
+			name:     "torus",
+			layout:   columnOfMines(TorusTopology),
+			expected: Metrics{BBBV: 1, Openings: []int{12}, Density: 4.0 / 16},
+		},
+		{
+			name: "flat",
+			// (1, 0) touches only numbers, and (1, 1) touches the mine
+			layout:   layoutWithMines(4, 2, FlatTopology, Coordinates{0, 0}),
+			expected: Metrics{BBBV: 2, Openings: []int{6}, IsolatedNumbers: 1, Islands: 1, Density: 1.0 / 8},
+		},
+		{
+			name: "hex",
+			// odd rows are shifted right, so (1, 1) doesn't touch the mine and opens (1, 0)
+			layout:   layoutWithMines(4, 2, HexTopology, Coordinates{0, 0}),
+			expected: Metrics{BBBV: 1, Openings: []int{7}, Density: 1.0 / 8},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics, err := AnalyzeLayout(test.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(metrics, test.expected) {
+				t.Errorf("metrics are %+v, expected %+v", metrics, test.expected)
+			}
+		})
+	}
+}
+
+func TestSolvedBBBV(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology Topology
+		dig      []Coordinates
+		expected int
+	}{
+		{"nothing dug", FlatTopology, nil, 0},
+		{"opening", FlatTopology, []Coordinates{{0, 3}}, 1},
+		{"number of an opening", FlatTopology, []Coordinates{{0, 2}}, 0},
+		{"island number", FlatTopology, []Coordinates{{0, 0}}, 1},
+		{"all", FlatTopology, []Coordinates{{0, 3}, {0, 0}, {1, 0}, {2, 0}, {3, 0}}, 5},
+		{"torus opening", TorusTopology, []Coordinates{{0, 3}}, 1},
+		{"torus number of the opening", TorusTopology, []Coordinates{{0, 0}}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			layout := columnOfMines(test.topology)
+			f := newEmptyMinefield(layout.Width, layout.Height, layout.Topology, layout.Mines.Len())
+			f.placeMines(layout.Mines)
+			for _, coord := range test.dig {
+				if _, err := f.Dig(coord.Row, coord.Col); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if solved := f.solvedBBBV(); solved != test.expected {
+				t.Errorf("solved 3BV is %v, expected %v", solved, test.expected)
+			}
+		})
+	}
+}
@@ -9,13 +9,22 @@ import (
 )
 
 type Coordinates struct {
-	Row, Col int
+	Row int `json:"row"`
+	Col int `json:"col"`
 }
 
 func (c *Coordinates) Equals(row, col int) bool {
 	return c != nil && c.Row == row && c.Col == col
 }
 
+// compareCoordinates orders coordinates row by row
+func compareCoordinates(a, b Coordinates) int {
+	if a.Row != b.Row {
+		return a.Row - b.Row
+	}
+	return a.Col - b.Col
+}
+
 func indexToCoordinates(index, width int) Coordinates {
 	return Coordinates{
 		Row: index / width,
@@ -24,39 +33,95 @@ func indexToCoordinates(index, width int) Coordinates {
 }
 
 type MineList interface {
+	// Randomize adds random mines on the free cells - the cells that are neither mined nor excluded.
+	// Every choice of free cells is equally likely. When there are fewer free cells than requested, all of them are mined.
 	Randomize(mineCount int)
+	RandomizeWith(rnd *rand.Rand, mineCount int)
 	Add(row, col int) error
+	// Remove clears a mine, clearing a cell without a mine does nothing
+	Remove(row, col int) error
+	// Move moves a mine to a cell without a mine
+	Move(from, to Coordinates) error
+	// Exclude keeps cells clear of random mines, mines added explicitly are not affected
+	Exclude(cells ...Coordinates) error
+	// ExcludeAround excludes a cell and the cells around it on a minefield with the given topology
+	ExcludeAround(topology Topology, row, col int) error
 	Len() int
 	IsMine(row, col int) bool
 	Coordinates() []Coordinates
 }
 
 type mineList struct {
-	width   int
-	height  int
-	mineSet map[Coordinates]struct{}
+	width    int
+	height   int
+	mineSet  map[Coordinates]struct{}
+	excluded map[Coordinates]struct{}
 }
 
 func NewMineList(width, height, capacity int) MineList {
 	return &mineList{
-		width:   width,
-		height:  height,
-		mineSet: make(map[Coordinates]struct{}, capacity),
+		width:    width,
+		height:   height,
+		mineSet:  make(map[Coordinates]struct{}, capacity),
+		excluded: make(map[Coordinates]struct{}),
 	}
 }
 
 func (l *mineList) Randomize(mineCount int) {
-	initialLen := l.Len()
-	maxIndex := l.width * l.height
+	l.RandomizeWith(rand.New(rand.NewSource(rand.Int63())), mineCount)
+}
 
-	for l.Len()-initialLen < mineCount {
-		index := rand.Intn(maxIndex)
-		l.mineSet[indexToCoordinates(index, l.width)] = struct{}{}
+// RandomizeWith adds random mines drawn from the given source, so the same seeded source always yields the same layout.
+// While at least half of the cells stay free, random cells are drawn and the taken ones are redrawn, which keeps the
+// layouts of sparse boards small to compute. Denser boards shuffle the free cells instead (a partial Fisher–Yates
+// shuffle), so the cost never depends on how many draws happen to collide.
+func (l *mineList) RandomizeWith(rnd *rand.Rand, mineCount int) {
+	size := l.width * l.height
+	free := size - l.Len()
+	for coord := range l.excluded {
+		if !l.IsMine(coord.Row, coord.Col) {
+			free--
+		}
+	}
+	mineCount = min(mineCount, free)
+
+	if (free-mineCount)*2 >= size {
+		for added := 0; added < mineCount; {
+			coord := indexToCoordinates(rnd.Intn(size), l.width)
+			if !l.isFree(coord) {
+				continue
+			}
+			l.mineSet[coord] = struct{}{}
+			added++
+		}
+		return
+	}
+
+	freeCells := make([]Coordinates, 0, free)
+	for index := 0; index < size; index++ {
+		if coord := indexToCoordinates(index, l.width); l.isFree(coord) {
+			freeCells = append(freeCells, coord)
+		}
+	}
+	for i := 0; i < mineCount; i++ {
+		j := i + rnd.Intn(len(freeCells)-i)
+		freeCells[i], freeCells[j] = freeCells[j], freeCells[i]
+		l.mineSet[freeCells[i]] = struct{}{}
 	}
 }
 
+func (l *mineList) isFree(coord Coordinates) bool {
+	_, isMine := l.mineSet[coord]
+	_, isExcluded := l.excluded[coord]
+	return !isMine && !isExcluded
+}
+
+func (l *mineList) isValid(row, col int) bool {
+	return row >= 0 && row < l.height && col >= 0 && col < l.width
+}
+
 func (l *mineList) Add(row, col int) error {
-	if row < 0 || row >= l.height || col < 0 || col >= l.width {
+	if !l.isValid(row, col) {
 		return fmt.Errorf("invalid mine coordinates")
 	}
 
@@ -64,6 +129,52 @@ func (l *mineList) Add(row, col int) error {
 	return nil
 }
 
+func (l *mineList) Remove(row, col int) error {
+	if !l.isValid(row, col) {
+		return fmt.Errorf("invalid mine coordinates")
+	}
+
+	delete(l.mineSet, Coordinates{Row: row, Col: col})
+	return nil
+}
+
+func (l *mineList) Move(from, to Coordinates) error {
+	if !l.isValid(from.Row, from.Col) || !l.isValid(to.Row, to.Col) {
+		return fmt.Errorf("invalid mine coordinates")
+	}
+	if !l.IsMine(from.Row, from.Col) {
+		return fmt.Errorf("no mine to move at (%v, %v)", from.Row, from.Col)
+	}
+	if l.IsMine(to.Row, to.Col) {
+		return fmt.Errorf("a mine is already at (%v, %v)", to.Row, to.Col)
+	}
+
+	delete(l.mineSet, from)
+	l.mineSet[to] = struct{}{}
+	return nil
+}
+
+func (l *mineList) Exclude(cells ...Coordinates) error {
+	for _, coord := range cells {
+		if !l.isValid(coord.Row, coord.Col) {
+			return fmt.Errorf("invalid excluded coordinates")
+		}
+	}
+
+	for _, coord := range cells {
+		l.excluded[coord] = struct{}{}
+	}
+	return nil
+}
+
+func (l *mineList) ExcludeAround(topology Topology, row, col int) error {
+	if !l.isValid(row, col) {
+		return fmt.Errorf("invalid excluded coordinates")
+	}
+	center := Coordinates{Row: row, Col: col}
+	return l.Exclude(append(topology.SurroundingCells(l.width, l.height, center), center)...)
+}
+
 func (l *mineList) Len() int {
 	return len(l.mineSet)
 }
@@ -0,0 +1,46 @@
+package minesweeper
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExcludeAround(t *testing.T) {
+	tests := []struct {
+		topology Topology
+		// free are the cells left without mines when every other cell is mined
+		free []Coordinates
+	}{
+		{FlatTopology, []Coordinates{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+		{TorusTopology, []Coordinates{{0, 0}, {0, 1}, {0, 4}, {1, 0}, {1, 1}, {1, 4}, {3, 0}, {3, 1}, {3, 4}}},
+		{HexTopology, []Coordinates{{0, 0}, {0, 1}, {1, 0}}},
+	}
+	for _, test := range tests {
+		t.Run(test.topology.String(), func(t *testing.T) {
+			mines := NewMineList(5, 4, 20)
+			if err := mines.ExcludeAround(test.topology, 0, 0); err != nil {
+				t.Fatal(err)
+			}
+			mines.Randomize(20)
+
+			var free []Coordinates
+			for row := 0; row < 4; row++ {
+				for col := 0; col < 5; col++ {
+					if !mines.IsMine(row, col) {
+						free = append(free, Coordinates{Row: row, Col: col})
+					}
+				}
+			}
+			if !slices.Equal(free, test.free) {
+				t.Errorf("free cells are %v, expected %v", free, test.free)
+			}
+		})
+	}
+}
+
+func TestExcludeAroundInvalidCell(t *testing.T) {
+	mines := NewMineList(5, 4, 20)
+	if err := mines.ExcludeAround(FlatTopology, 4, 0); err == nil {
+		t.Error("excluding around a cell outside the minefield should fail")
+	}
+}
@@ -3,50 +3,108 @@ package minesweeper
 var drawHeader = true
 
 type Minefield interface {
-	Flag(row, col int) (Coordinates, error)
-	Unflag(row, col int) (Coordinates, error)
-	ToggleFlag(row, col int) (Coordinates, error)
-	Dig(row, col int) ([]Coordinates, error)
+	// Flag, Unflag, ToggleFlag, Dig and Chord return the cells changed by the move
+	Flag(row, col int) (ChangeSet, error)
+	Unflag(row, col int) (ChangeSet, error)
+	ToggleFlag(row, col int) (ChangeSet, error)
+	Dig(row, col int) (ChangeSet, error)
+	// Chord digs all unflagged cells around a dugged cell, when the number of flags around it matches its mines count
+	Chord(row, col int) (ChangeSet, error)
 	GameStatus() GameStatus
 	CellStatus(row, col int) CellStatus
 	AllCellStatus() [][]CellStatus
 	FlagsLeft() int
 	Width() int
 	Height() int
+	History
+	Observable
+	// State returns a serializable snapshot of the game
+	State() GameState
+	// Seed returns the seed the mine layout was generated from, 0 for layouts that were not generated
+	Seed() int64
+	Topology() Topology
 }
 
 type minefield struct {
-	width  int
-	height int
-	cells  [][]*cell
-	mines  []Coordinates
-	flags map[Coordinates]struct{}
-	dugCount int
-	status   GameStatus
+	width    int
+	height   int
+	topology Topology
+	// cells are stored row by row, see index
+	cells     []cell
+	mines     []Coordinates
+	mineCount int
+	flags     map[Coordinates]struct{}
+	dugCount  int
+	status    GameStatus
+	seed      int64
+	// generation options, kept to recreate the layout of a saved minefield
+	firstClick FirstClickPolicy
+	noGuess    *NoGuessLimits
+	// layout is set while mines placement is deferred to the first dig
+	layout layoutFunc
+
+	// moves history, current is the move being recorded
+	current   *move
+	undoStack []*move
+	redoStack []*move
+
+	subscriptions []*subscription
+	// touched marks the cells recorded by the current move
+	touched []bool
 }
 
 func NewMinefield(width, height int, mines MineList) Minefield {
-	f := &minefield{
-		width:  width,
-		height: height,
-		mines:  mines.Coordinates(),
-		flags: make(map[Coordinates]struct{}, mines.Len()),
-		cells: make([][]*cell, height),
-	}
-
-	// init cells & mines
-	for row := range f.cells {
-		f.cells[row] = make([]*cell, width)
-		for col := range f.cells[row] {
-			isMine := mines.IsMine(row, col)
-			f.cells[row][col] = newCell(isMine)
-		}
+	return newMinefield(width, height, FlatTopology, mines)
+}
+
+func newMinefield(width, height int, topology Topology, mines MineList) *minefield {
+	f := newEmptyMinefield(width, height, topology, mines.Len())
+	f.placeMines(mines)
+	return f
+}
+
+// newDeferredMinefield creates a minefield that places its mines on the first dig
+func newDeferredMinefield(width, height int, topology Topology, mineCount int, layout layoutFunc) *minefield {
+	f := newEmptyMinefield(width, height, topology, mineCount)
+	f.layout = layout
+	return f
+}
+
+func newEmptyMinefield(width, height int, topology Topology, mineCount int) *minefield {
+	return &minefield{
+		width:     width,
+		height:    height,
+		topology:  topology,
+		mineCount: mineCount,
+		flags:     make(map[Coordinates]struct{}, mineCount),
+		cells:     make([]cell, width*height),
+		touched:   make([]bool, width*height),
+	}
+}
+
+// index returns the position of a cell in the cells slice
+func (f *minefield) index(row, col int) int {
+	return row*f.width + col
+}
+
+func (f *minefield) coordinates(index int) Coordinates {
+	return indexToCoordinates(index, f.width)
+}
+
+// neighbors fills buf with the indexes of the cells around a cell, and returns the filled part of it
+func (f *minefield) neighbors(index int, buf *[8]int) []int {
+	return f.topology.neighbors(f.width, f.height, index, buf)
+}
+
+func (f *minefield) placeMines(mines MineList) {
+	f.mines = mines.Coordinates()
+	f.mineCount = len(f.mines)
+	for _, coord := range f.mines {
+		f.cells[f.index(coord.Row, coord.Col)].isMine = true
 	}
 
 	// init minesAround counter
 	f.initMinesAround()
-
-	return f
 }
 
 func (f *minefield) initMinesAround() {
@@ -56,186 +114,208 @@ func (f *minefield) initMinesAround() {
 }
 
 func (f *minefield) incrementMinesAround(mineCoord Coordinates) {
-	for _, cell := range f.getSurroundingCells(mineCoord) {
-		f.cells[cell.Row][cell.Col].minesAround++
-	}
-}
-
-func (f *minefield) getSurroundingCells(mineCoord Coordinates) []Coordinates {
-	surroundingCells := []Coordinates{
-		// row above
-		{Row: mineCoord.Row - 1, Col: mineCoord.Col - 1},
-		{Row: mineCoord.Row - 1, Col: mineCoord.Col},
-		{Row: mineCoord.Row - 1, Col: mineCoord.Col + 1},
-		// same row
-		{Row: mineCoord.Row, Col: mineCoord.Col - 1},
-		{Row: mineCoord.Row, Col: mineCoord.Col + 1},
-		// row below
-		{Row: mineCoord.Row + 1, Col: mineCoord.Col - 1},
-		{Row: mineCoord.Row + 1, Col: mineCoord.Col},
-		{Row: mineCoord.Row + 1, Col: mineCoord.Col + 1},
-	}
-
-	res := make([]Coordinates, 0, len(surroundingCells))
-	for _, cell := range surroundingCells {
-		// skip out of bounds cells
-		if cell.Row >= 0 && cell.Row < f.height &&
-			cell.Col >= 0 && cell.Col < f.width {
-			res = append(res, cell)
-		}
+	var buf [8]int
+	for _, n := range f.neighbors(f.index(mineCoord.Row, mineCoord.Col), &buf) {
+		f.cells[n].minesAround++
 	}
+}
 
-	return res
+func (f *minefield) Flag(row, col int) (ChangeSet, error) {
+	return f.doMove(func() error { return f.flag(row, col) })
 }
 
-func (f *minefield) Flag(row, col int) (Coordinates, error) {
+func (f *minefield) flag(row, col int) error {
 	if f.status != GameOn {
-		return Coordinates{-1, -1}, &GameOverError{}
+		return &GameOverError{}
 	}
-	if len(f.flags) == len(f.mines) {
+	if len(f.flags) == f.mineCount {
 		// number of flags cannot exceed the number of mines
-		return Coordinates{-1, -1}, &OutOfFlagsError{}
+		return &OutOfFlagsError{}
 	}
 	exists, cell := f.getCell(row, col)
 	if !exists {
-		return Coordinates{-1, -1}, &InvalidCoordinatesError{}
+		return &InvalidCoordinatesError{}
 	}
 	if cell.isFlagged {
-		return Coordinates{-1, -1}, &AlreadyFlaggedError{}
+		return &AlreadyFlaggedError{}
 	}
 	if cell.isDug {
-		return Coordinates{-1, -1}, &AlreadyDuggedError{}
+		return &AlreadyDuggedError{}
 	}
 
-	cell.isFlagged = true
 	coord := Coordinates{row, col}
+	f.touch(f.index(row, col))
+	cell.isFlagged = true
 	f.flags[coord] = struct{}{}
-	return coord, nil
+	return nil
+}
+
+func (f *minefield) Unflag(row, col int) (ChangeSet, error) {
+	return f.doMove(func() error { return f.unflag(row, col) })
 }
 
-func (f *minefield) Unflag(row, col int) (Coordinates, error) {
+func (f *minefield) unflag(row, col int) error {
 	if f.status != GameOn {
-		return Coordinates{-1, -1}, &GameOverError{}
+		return &GameOverError{}
 	}
 	exists, cell := f.getCell(row, col)
 	if !exists {
-		return Coordinates{-1, -1}, &InvalidCoordinatesError{}
+		return &InvalidCoordinatesError{}
 	}
 	if !cell.isFlagged {
-		return Coordinates{-1, -1}, &AlreadyUnflaggedError{}
+		return &AlreadyUnflaggedError{}
 	}
 	if cell.isDug {
-		return Coordinates{-1, -1}, &AlreadyDuggedError{}
+		return &AlreadyDuggedError{}
 	}
 
-	cell.isFlagged = false
 	coord := Coordinates{row, col}
+	f.touch(f.index(row, col))
+	cell.isFlagged = false
 	delete(f.flags, coord)
-	return coord, nil
+	return nil
 }
 
-func (f *minefield) ToggleFlag(row, col int) (Coordinates, error) {
-	if f.status != GameOn {
-		return Coordinates{-1, -1}, &GameOverError{}
-	}
-	exists, cell := f.getCell(row, col)
-	if !exists {
-		return Coordinates{-1, -1}, &InvalidCoordinatesError{}
-	}
-	if !cell.isFlagged {
-		return f.Flag(row, col)
-	}
-	return f.Unflag(row, col)
+func (f *minefield) ToggleFlag(row, col int) (ChangeSet, error) {
+	return f.doMove(func() error {
+		if f.status != GameOn {
+			return &GameOverError{}
+		}
+		exists, cell := f.getCell(row, col)
+		if !exists {
+			return &InvalidCoordinatesError{}
+		}
+		if !cell.isFlagged {
+			return f.flag(row, col)
+		}
+		return f.unflag(row, col)
+	})
+}
+
+func (f *minefield) Dig(row, col int) (ChangeSet, error) {
+	return f.doMove(func() error { return f.dig(row, col) })
 }
 
-func (f *minefield) Dig(row, col int) ([]Coordinates, error) {
+func (f *minefield) dig(row, col int) error {
 	if f.status != GameOn {
-		return nil, &GameOverError{}
+		return &GameOverError{}
 	}
 	exists, cell := f.getCell(row, col)
 	if !exists {
-		return nil, &InvalidCoordinatesError{}
+		return &InvalidCoordinatesError{}
 	}
 	if cell.isFlagged {
-		return nil, &AlreadyFlaggedError{}
+		return &AlreadyFlaggedError{}
 	}
-	if !f.digOne(cell, row, col) {
-		return nil, &AlreadyDuggedError{}
+	if f.layout != nil {
+		// first dig - place the mines according to the first click policy
+		mines, err := f.layout(Coordinates{Row: row, Col: col})
+		if err != nil {
+			return err
+		}
+		f.placeMines(mines)
+		f.layout = nil
+	}
+	if !f.digOne(f.index(row, col)) {
+		return &AlreadyDuggedError{}
 	}
+	f.current.dug = append(f.current.dug, Coordinates{Row: row, Col: col})
 
 	// game lost
 	if cell.isMine {
 		f.status = Lost
-
-		// collect changed cells - the dugged cell + wrong flagged cells + unflagged mines
-		changes := []Coordinates{{row,col}}
-		changes = append(changes, f.getWronglyFlaggedCells()...)
-		changes = append(changes, f.getUnflaggedMines()...)
-		return changes, nil
+		return nil
 	}
 
 	// expose safe cells
-	dugged := f.autoDig(cell, Coordinates{Row: row, Col: col})
+	f.autoDig(f.index(row, col))
 
 	// winning condition - all non-mine cell are dug
-	if f.dugCount == f.width*f.height-len(f.mines) {
+	if f.dugCount == f.width*f.height-f.mineCount {
 		f.status = Won
-
-		// collect changed cells - the dugged cell + auto dugged cells + unflagged mines
-		return append(dugged, f.getUnflaggedMines()...), nil 
 	}
+	return nil
+}
 
-	return dugged, nil
+func (f *minefield) Chord(row, col int) (ChangeSet, error) {
+	return f.doMove(func() error { return f.chord(row, col) })
 }
 
-func (f *minefield) getWronglyFlaggedCells() []Coordinates {
-	wrongFlags := make([]Coordinates, 0)
-	for flagCoord := range f.flags {
-		if found, flagCell := f.getCell(flagCoord.Row, flagCoord.Col); found && !flagCell.isMine {
-			wrongFlags = append(wrongFlags, flagCoord)
+func (f *minefield) chord(row, col int) error {
+	if f.status != GameOn {
+		return &GameOverError{}
+	}
+	exists, cell := f.getCell(row, col)
+	if !exists {
+		return &InvalidCoordinatesError{}
+	}
+	if !cell.isDug {
+		return &NotDuggedError{}
+	}
+
+	var buf [8]int
+	surroundingCells := f.neighbors(f.index(row, col), &buf)
+	flagsAround := 0
+	for _, n := range surroundingCells {
+		if f.cells[n].isFlagged {
+			flagsAround++
 		}
 	}
-	return wrongFlags
-}
+	if cell.minesAround == 0 || flagsAround != int(cell.minesAround) {
+		return &ChordUnsatisfiedError{}
+	}
+
+	for _, n := range surroundingCells {
+		currCell := &f.cells[n]
+		if currCell.isDug || currCell.isFlagged {
+			continue
+		}
 
-func (f *minefield) getUnflaggedMines() []Coordinates {
-	unflagged := make([]Coordinates, 0)
-	for _, mineCoord := range f.mines {
-		if found, mineCell := f.getCell(mineCoord.Row, mineCoord.Col); found && !mineCell.isDug && !mineCell.isFlagged {
-			unflagged = append(unflagged, mineCoord)
+		// a wrong flag means one of the dugged cells is a mine, and the game is lost
+		coord := f.coordinates(n)
+		if err := f.dig(coord.Row, coord.Col); err != nil {
+			continue
+		}
+		if f.status != GameOn {
+			break
 		}
 	}
-	return unflagged
+	return nil
 }
 
-func (f *minefield) digOne(cell *cell, row, col int) bool {
+func (f *minefield) digOne(index int) bool {
+	cell := &f.cells[index]
 	if cell.isDug {
 		return false
 	}
 
-	f.Unflag(row, col)
+	if cell.isFlagged {
+		coord := f.coordinates(index)
+		f.unflag(coord.Row, coord.Col)
+	}
+	f.touch(index)
 	cell.isDug = true
 	f.dugCount++
 	return true
 }
 
-// autoDig - if cell don't contain a mine and not surrounded by mines, continue auto-digging its surroundings recursively
-func (f *minefield) autoDig(cell *cell, coord Coordinates) []Coordinates {
-	dugged := []Coordinates{coord}
-	f.autoDigRecursive(cell, coord, dugged)
-	return dugged
-}
-
-func (f *minefield) autoDigRecursive(cell *cell, coord Coordinates, dugged []Coordinates) {
-	if cell.minesAround > 0 {
+// autoDig - if cell don't contain a mine and not surrounded by mines, auto-dig its surroundings.
+// The flood keeps its pending cells on a stack rather than recursing, so openings of any size fit.
+func (f *minefield) autoDig(index int) {
+	if f.cells[index].minesAround > 0 {
 		return
 	}
 
-	for _, currCoord := range f.getSurroundingCells(coord) {
-		currCell := f.cells[currCoord.Row][currCoord.Col]
-		if f.digOne(currCell, currCoord.Row, currCoord.Col) {
-			dugged = append(dugged, currCoord)
-			f.autoDigRecursive(currCell, currCoord, dugged)
+	var buf [8]int
+	stack := []int{index}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, n := range f.neighbors(current, &buf) {
+			if f.digOne(n) && f.cells[n].minesAround == 0 {
+				stack = append(stack, n)
+			}
 		}
 	}
 }
@@ -245,12 +325,15 @@ func (f *minefield) GameStatus() GameStatus {
 }
 
 func (f *minefield) AllCellStatus() [][]CellStatus {
+	// all rows share a single allocation
+	statuses := make([]CellStatus, len(f.cells))
+	for i := range f.cells {
+		statuses[i] = cellStatusOf(f.cells[i], f.status)
+	}
+
 	res := make([][]CellStatus, f.height)
-	for row := range f.cells {
-		res[row] = make([]CellStatus, f.width)
-		for col := range f.cells[row] {
-			res[row][col] = f.CellStatus(row, col)
-		}
+	for row := range res {
+		res[row] = statuses[row*f.width : (row+1)*f.width : (row+1)*f.width]
 	}
 	return res
 }
@@ -260,8 +343,12 @@ func (f *minefield) CellStatus(row, col int) CellStatus {
 	if !exists {
 		return Unknown
 	}
+	return cellStatusOf(*c, f.status)
+}
 
-	if f.status == Lost {
+// cellStatusOf returns the status of a cell as shown in a game with the given status
+func cellStatusOf(c cell, status GameStatus) CellStatus {
+	if status == Lost {
 		if c.isFlagged {
 			if c.isMine {
 				return Flagged
@@ -274,7 +361,7 @@ func (f *minefield) CellStatus(row, col int) CellStatus {
 			}
 			return Mine
 		}
-	} else if f.status == Won {
+	} else if status == Won {
 		// auto-flag mines if won
 		if c.isMine {
 			return Flagged
@@ -294,7 +381,7 @@ func (f *minefield) CellStatus(row, col int) CellStatus {
 }
 
 func (f *minefield) FlagsLeft() int {
-	return len(f.mines) - len(f.flags)
+	return f.mineCount - len(f.flags)
 }
 
 func (f *minefield) Width() int {
@@ -305,9 +392,17 @@ func (f *minefield) Height() int {
 	return f.height
 }
 
+func (f *minefield) Seed() int64 {
+	return f.seed
+}
+
+func (f *minefield) Topology() Topology {
+	return f.topology
+}
+
 func (f *minefield) getCell(row, col int) (bool, *cell) {
 	if row < 0 || row >= f.height || col < 0 || col >= f.width {
 		return false, nil
 	}
-	return true, f.cells[row][col]
+	return true, &f.cells[f.index(row, col)]
 }
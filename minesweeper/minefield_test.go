@@ -0,0 +1,72 @@
+package minesweeper
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// a large sparse board, where a single dig floods most of the cells
+const (
+	benchmarkWidth  = 2000
+	benchmarkHeight = 2000
+	benchmarkMines  = benchmarkWidth * benchmarkHeight / 100
+)
+
+func newBenchmarkMines() MineList {
+	mines := NewMineList(benchmarkWidth, benchmarkHeight, benchmarkMines)
+	// keep the top left corner clear, for the dig benchmarks to start from
+	mines.ExcludeAround(FlatTopology, 0, 0)
+	mines.RandomizeWith(rand.New(rand.NewSource(1)), benchmarkMines)
+	return mines
+}
+
+func BenchmarkNewMinefield(b *testing.B) {
+	mines := newBenchmarkMines()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewMinefield(benchmarkWidth, benchmarkHeight, mines)
+	}
+}
+
+func BenchmarkDig(b *testing.B) {
+	mines := newBenchmarkMines()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		f := NewMinefield(benchmarkWidth, benchmarkHeight, mines)
+		b.StartTimer()
+
+		if _, err := f.Dig(0, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUndoDig(b *testing.B) {
+	f := NewMinefield(benchmarkWidth, benchmarkHeight, newBenchmarkMines())
+	if _, err := f.Dig(0, 0); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Undo(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := f.Redo(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAllCellStatus(b *testing.B) {
+	f := NewMinefield(benchmarkWidth, benchmarkHeight, newBenchmarkMines())
+	if _, err := f.Dig(0, 0); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.AllCellStatus()
+	}
+}
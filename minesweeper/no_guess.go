@@ -0,0 +1,62 @@
+package minesweeper
+
+import (
+	"math/rand"
+	"time"
+)
+
+// isSolvableFrom plays the layout forward from the first dig, using deductions only - like solver.Solve, which shares
+// the deduction rules, it flags and digs the forced cells until the game is won or the next move is a guess
+func isSolvableFrom(width, height int, topology Topology, mines MineList, first Coordinates) bool {
+	f := newMinefield(width, height, topology, mines)
+	if _, err := f.Dig(first.Row, first.Col); err != nil {
+		return false
+	}
+
+	for f.status == GameOn {
+		safe, mines := forcedCells(f.AllCellStatus(), topology, f.FlagsLeft())
+		if len(safe) == 0 && len(mines) == 0 {
+			// stuck - the next move is a guess
+			return false
+		}
+
+		for _, coord := range mines {
+			f.Flag(coord.Row, coord.Col)
+		}
+		for _, coord := range safe {
+			f.Dig(coord.Row, coord.Col)
+		}
+	}
+	return f.status == Won
+}
+
+// NoGuessLimits caps the search for a minefield that is solvable without guessing
+type NoGuessLimits struct {
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+var DefaultNoGuessLimits = NoGuessLimits{
+	MaxAttempts: 5000,
+	Timeout:     2 * time.Second,
+}
+
+// newNoGuessLayout places the mines so the first dig opens an area and the rest of the minefield can be solved from there
+// by deduction alone. Layouts that require guessing are rejected until one solvable layout is found or the limits are reached.
+// Every first dig searches from the seed again, so a dig that failed can be retried on another cell, and the layout only
+// depends on the seed and the cell it was found from.
+func newNoGuessLayout(width, height int, topology Topology, mineCount int, limits NoGuessLimits, seed int64) layoutFunc {
+	return func(first Coordinates) (MineList, error) {
+		rnd := rand.New(rand.NewSource(seed))
+		zone := append(topology.SurroundingCells(width, height, first), first)
+		deadline := time.Now().Add(limits.Timeout)
+
+		for attempt := 0; attempt < limits.MaxAttempts && time.Now().Before(deadline); attempt++ {
+			mines := placeMinesAround(width, height, mineCount, zone, rnd)
+			if isSolvableFrom(width, height, topology, mines, first) {
+				return mines, nil
+			}
+		}
+		return nil, &NoGuessInfeasibleError{}
+	}
+}
@@ -0,0 +1,176 @@
+package probability
+
+import (
+	"math"
+	"slices"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// area - frontier cells connected by the numbers they share
+type area struct {
+	cells       []minesweeper.Coordinates
+	constraints []constraint
+}
+
+// distribution - the number of mines layouts of an area, by the number of mines in it.
+// mineWays[c][k] is the number of layouts with k mines in which cell c is a mine.
+// Only the ratios matter, so both may be scaled by the same factor.
+type distribution struct {
+	ways     []float64
+	mineWays [][]float64
+}
+
+func newDistribution(cellCount int) distribution {
+	d := distribution{
+		ways:     make([]float64, cellCount+1),
+		mineWays: make([][]float64, cellCount),
+	}
+	for c := range d.mineWays {
+		d.mineWays[c] = make([]float64, cellCount+1)
+	}
+	return d
+}
+
+// enumerate counts all mines layouts of the area that satisfy its numbers.
+// ok is false if maxSteps was reached before all layouts were counted.
+func (a *area) enumerate(maxSteps int) (d distribution, ok bool) {
+	index := make(map[minesweeper.Coordinates]int, len(a.cells))
+	for i, coord := range a.cells {
+		index[coord] = i
+	}
+
+	// constraints of each cell, mines placed and cells left open in each constraint
+	cellConstraints := make([][]int, len(a.cells))
+	placed := make([]int, len(a.constraints))
+	open := make([]int, len(a.constraints))
+	for i, c := range a.constraints {
+		open[i] = len(c.cells)
+		for _, coord := range c.cells {
+			cellConstraints[index[coord]] = append(cellConstraints[index[coord]], i)
+		}
+	}
+
+	d = newDistribution(len(a.cells))
+	isMine := make([]bool, len(a.cells))
+	steps := 0
+
+	var place func(cell, mines int) bool
+	place = func(cell, mines int) bool {
+		if steps++; steps > maxSteps {
+			return false
+		}
+
+		if cell == len(a.cells) {
+			d.ways[mines]++
+			for c := range isMine {
+				if isMine[c] {
+					d.mineWays[c][mines]++
+				}
+			}
+			return true
+		}
+
+		for _, mine := range []bool{false, true} {
+			value := 0
+			if mine {
+				value = 1
+			}
+
+			fits := true
+			for _, i := range cellConstraints[cell] {
+				p := placed[i] + value
+				if p > a.constraints[i].mines || p+open[i]-1 < a.constraints[i].mines {
+					fits = false
+					break
+				}
+			}
+			if !fits {
+				continue
+			}
+
+			for _, i := range cellConstraints[cell] {
+				placed[i] += value
+				open[i]--
+			}
+			isMine[cell] = mine
+			done := place(cell+1, mines+value)
+			isMine[cell] = false
+			for _, i := range cellConstraints[cell] {
+				placed[i] -= value
+				open[i]++
+			}
+
+			if !done {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !place(0, 0) {
+		return distribution{}, false
+	}
+	d.normalize()
+	return d, true
+}
+
+// estimate approximates the area when it's too large to enumerate.
+// Each cell gets the highest ratio of missing mines to undugged cells among its numbers,
+// and the area is assumed to hold the expected number of mines.
+func (a *area) estimate() distribution {
+	risk := make(map[minesweeper.Coordinates]float64, len(a.cells))
+	for _, c := range a.constraints {
+		ratio := float64(c.mines) / float64(len(c.cells))
+		for _, coord := range c.cells {
+			risk[coord] = max(risk[coord], ratio)
+		}
+	}
+
+	expected := 0.0
+	for _, coord := range a.cells {
+		expected += risk[coord]
+	}
+	k := int(math.Round(expected))
+
+	d := newDistribution(len(a.cells))
+	d.ways[k] = 1
+	for c, coord := range a.cells {
+		d.mineWays[c][k] = risk[coord]
+	}
+	return d
+}
+
+// normalize scales the distribution so the most common mines count has weight 1
+func (d *distribution) normalize() {
+	peak := slices.Max(d.ways)
+	if peak == 0 {
+		return
+	}
+	for k := range d.ways {
+		d.ways[k] /= peak
+	}
+	for c := range d.mineWays {
+		for k := range d.mineWays[c] {
+			d.mineWays[c][k] /= peak
+		}
+	}
+}
+
+// convolve returns the number of layouts of all the areas together, by the total number of mines in them
+func convolve(dists ...distribution) []float64 {
+	res := []float64{1}
+	for _, d := range dists {
+		next := make([]float64, len(res)+len(d.ways)-1)
+		for i, a := range res {
+			if a == 0 {
+				continue
+			}
+			for j, b := range d.ways {
+				next[i+j] += a * b
+			}
+		}
+		res = next
+	}
+	return res
+}
@@ -0,0 +1,165 @@
+package probability
+
+import (
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// constraint - a revealed number, spread over its undugged surroundings
+type constraint struct {
+	cells []minesweeper.Coordinates
+	mines int
+}
+
+type board struct {
+	cells    [][]minesweeper.CellStatus
+	width    int
+	height   int
+	topology minesweeper.Topology
+}
+
+func newBoard(cells [][]minesweeper.CellStatus, topology minesweeper.Topology) *board {
+	return &board{
+		cells:    cells,
+		width:    len(cells[0]),
+		height:   len(cells),
+		topology: topology,
+	}
+}
+
+func isMine(status minesweeper.CellStatus) bool {
+	return status == minesweeper.Flagged || status == minesweeper.Mine || status == minesweeper.Explode
+}
+
+func minesAround(status minesweeper.CellStatus) (int, bool) {
+	if status >= minesweeper.MinesAround1 && status <= minesweeper.MinesAround8 {
+		return int(status), true
+	}
+	if status == minesweeper.NoMinesAround {
+		return 0, true
+	}
+	return 0, false
+}
+
+// knownProbabilities returns a grid with 1 for known mines and 0 for the rest
+func (b *board) knownProbabilities() [][]float64 {
+	res := make([][]float64, b.height)
+	for row := range b.cells {
+		res[row] = make([]float64, b.width)
+		for col, status := range b.cells[row] {
+			if isMine(status) {
+				res[row][col] = 1
+			}
+		}
+	}
+	return res
+}
+
+func (b *board) surroundingCells(coord minesweeper.Coordinates) []minesweeper.Coordinates {
+	return b.topology.SurroundingCells(b.width, b.height, coord)
+}
+
+// constraints returns the constraints of all revealed numbers. ok is false if a number can't be satisfied.
+func (b *board) constraints() (res []constraint, ok bool) {
+	for row := range b.cells {
+		for col, status := range b.cells[row] {
+			mines, isNumber := minesAround(status)
+			if !isNumber {
+				continue
+			}
+
+			c := constraint{mines: mines}
+			for _, n := range b.surroundingCells(minesweeper.Coordinates{Row: row, Col: col}) {
+				switch {
+				case b.cells[n.Row][n.Col] == minesweeper.Undugged:
+					c.cells = append(c.cells, n)
+				case isMine(b.cells[n.Row][n.Col]):
+					c.mines--
+				}
+			}
+
+			if c.mines < 0 || c.mines > len(c.cells) {
+				return nil, false
+			}
+			if len(c.cells) > 0 {
+				res = append(res, c)
+			}
+		}
+	}
+	return res, true
+}
+
+// frontierAreas splits the undugged cells bordering revealed numbers into areas that don't share any number.
+// ok is false if a number can't be satisfied.
+func (b *board) frontierAreas() (areas []*area, ok bool) {
+	constraints, ok := b.constraints()
+	if !ok {
+		return nil, false
+	}
+
+	byCell := make(map[minesweeper.Coordinates][]int)
+	for i, c := range constraints {
+		for _, coord := range c.cells {
+			byCell[coord] = append(byCell[coord], i)
+		}
+	}
+
+	visitedCells := make(map[minesweeper.Coordinates]struct{})
+	visitedConstraints := make([]bool, len(constraints))
+	for i := range constraints {
+		if visitedConstraints[i] {
+			continue
+		}
+
+		// breadth first, so cells sharing numbers are next to each other and the enumeration prunes early
+		a := &area{}
+		queue := []int{i}
+		visitedConstraints[i] = true
+		for len(queue) > 0 {
+			c := constraints[queue[0]]
+			queue = queue[1:]
+			a.constraints = append(a.constraints, c)
+
+			for _, coord := range c.cells {
+				if _, found := visitedCells[coord]; found {
+					continue
+				}
+				visitedCells[coord] = struct{}{}
+				a.cells = append(a.cells, coord)
+
+				for _, next := range byCell[coord] {
+					if !visitedConstraints[next] {
+						visitedConstraints[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+		areas = append(areas, a)
+	}
+	return areas, true
+}
+
+// interiorCells returns the undugged cells that don't border any revealed number
+func (b *board) interiorCells() []minesweeper.Coordinates {
+	res := make([]minesweeper.Coordinates, 0)
+	for row := range b.cells {
+		for col, status := range b.cells[row] {
+			if status != minesweeper.Undugged {
+				continue
+			}
+
+			coord := minesweeper.Coordinates{Row: row, Col: col}
+			isFrontier := false
+			for _, n := range b.surroundingCells(coord) {
+				if _, isNumber := minesAround(b.cells[n.Row][n.Col]); isNumber {
+					isFrontier = true
+					break
+				}
+			}
+			if !isFrontier {
+				res = append(res, coord)
+			}
+		}
+	}
+	return res
+}
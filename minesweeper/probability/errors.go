@@ -0,0 +1,13 @@
+package probability
+
+type InconsistentBoardError struct{}
+
+func (*InconsistentBoardError) Error() string {
+	return "no mines layout matches the board"
+}
+
+type InvalidBoardError struct{}
+
+func (*InvalidBoardError) Error() string {
+	return "board is empty or not rectangular"
+}
@@ -0,0 +1,49 @@
+package probability
+
+import (
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// GetHint is minesweeper.GetHint with guesses picked by their mine probability rather than estimated.
+// Like minesweeper.GetHint, flags are the player's guesses, so they are ignored.
+func GetHint(f minesweeper.Minefield) (minesweeper.Hint, error) {
+	hint, err := minesweeper.GetHint(f)
+	if err != nil || hint.Kind != minesweeper.GuessHint {
+		return hint, err
+	}
+
+	cells := f.AllCellStatus()
+	minesLeft := f.FlagsLeft()
+	for row := range cells {
+		for col := range cells[row] {
+			if cells[row][col] == minesweeper.Flagged {
+				cells[row][col] = minesweeper.Undugged
+				minesLeft++
+			}
+		}
+	}
+
+	res, err := CalculateCells(cells, f.Topology(), minesLeft, DefaultLimits)
+	if err != nil {
+		// keep the estimated guess
+		return hint, nil
+	}
+
+	best := minesweeper.Hint{Kind: minesweeper.GuessHint, MineProbability: 2, Exact: res.Exact}
+	for row := range cells {
+		for col := range cells[row] {
+			if cells[row][col] != minesweeper.Undugged || f.CellStatus(row, col) == minesweeper.Flagged {
+				continue
+			}
+			if p := res.At(row, col); p < best.MineProbability {
+				best.Cell = minesweeper.Coordinates{Row: row, Col: col}
+				best.MineProbability = p
+			}
+		}
+	}
+	if best.MineProbability > 1 {
+		// every undugged cell is flagged
+		return hint, nil
+	}
+	return best, nil
+}
@@ -0,0 +1,143 @@
+// Package probability computes the chance of each undugged cell to contain a mine,
+// given the revealed numbers, the flags and the number of mines left.
+package probability
+
+import (
+	"math"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// Limits caps the work spent on enumerating the mines layouts of a single frontier area.
+// Areas that exceed the limits are estimated instead of computed exactly.
+type Limits struct {
+	MaxSteps int
+}
+
+var DefaultLimits = Limits{
+	MaxSteps: 1 << 20,
+}
+
+// Result - the mine probability of each cell, indexed like AllCellStatus.
+// Dug cells are 0, flagged cells are 1.
+type Result struct {
+	Cells [][]float64
+	// Exact is false if some frontier area exceeded the limits and was estimated
+	Exact bool
+}
+
+// At returns the mine probability of the cell
+func (r Result) At(row, col int) float64 {
+	return r.Cells[row][col]
+}
+
+// Calculate computes the mine probabilities of a minefield in play, including minefields restored from a saved game.
+// Flags are trusted to be mines.
+func Calculate(f minesweeper.Minefield) (Result, error) {
+	if f.GameStatus() != minesweeper.GameOn {
+		return Result{}, &minesweeper.GameOverError{}
+	}
+	return CalculateCells(f.AllCellStatus(), f.Topology(), f.FlagsLeft(), DefaultLimits)
+}
+
+// CalculateCells computes the mine probabilities of a board, where minesLeft is the number of mines
+// hidden in the undugged cells. Flagged and revealed mines are trusted to be mines.
+func CalculateCells(cells [][]minesweeper.CellStatus, topology minesweeper.Topology, minesLeft int, limits Limits) (Result, error) {
+	if len(cells) == 0 || len(cells[0]) == 0 {
+		return Result{}, &InvalidBoardError{}
+	}
+	for row := range cells {
+		if len(cells[row]) != len(cells[0]) {
+			return Result{}, &InvalidBoardError{}
+		}
+	}
+
+	b := newBoard(cells, topology)
+	res := Result{Cells: b.knownProbabilities(), Exact: true}
+
+	areas, ok := b.frontierAreas()
+	if !ok {
+		return Result{}, &InconsistentBoardError{}
+	}
+
+	dists := make([]distribution, len(areas))
+	for i, a := range areas {
+		d, ok := a.enumerate(limits.MaxSteps)
+		if !ok {
+			d = a.estimate()
+			res.Exact = false
+		}
+		dists[i] = d
+	}
+
+	// weight of each total frontier mines count, by the number of ways to spread the rest in the other cells
+	interior := b.interiorCells()
+	weight := func(frontierMines int) float64 {
+		return binomialWeight(len(interior), minesLeft-frontierMines, minesLeft)
+	}
+
+	total := convolve(dists...)
+	var norm, interiorMines float64
+	for m, ways := range total {
+		w := ways * weight(m)
+		norm += w
+		interiorMines += w * float64(minesLeft-m)
+	}
+	if norm == 0 {
+		return Result{}, &InconsistentBoardError{}
+	}
+
+	if len(interior) > 0 {
+		p := interiorMines / norm / float64(len(interior))
+		for _, coord := range interior {
+			res.Cells[coord.Row][coord.Col] = p
+		}
+	}
+
+	for i, a := range areas {
+		others := convolve(append(append([]distribution{}, dists[:i]...), dists[i+1:]...)...)
+		d := dists[i]
+
+		var areaNorm float64
+		cellWeights := make([]float64, len(a.cells))
+		for k := range d.ways {
+			for j, ways := range others {
+				w := ways * weight(k+j)
+				if w == 0 {
+					continue
+				}
+				areaNorm += d.ways[k] * w
+				for c := range a.cells {
+					cellWeights[c] += d.mineWays[c][k] * w
+				}
+			}
+		}
+		if areaNorm == 0 {
+			return Result{}, &InconsistentBoardError{}
+		}
+
+		for c, coord := range a.cells {
+			res.Cells[coord.Row][coord.Col] = cellWeights[c] / areaNorm
+		}
+	}
+
+	return res, nil
+}
+
+// binomialWeight returns C(n, k), scaled down by a constant that depends only on n and total.
+// The scale keeps the weights of boards with many undugged cells inside the float range.
+func binomialWeight(n, k, total int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	// the largest C(n, k) over 0 <= k <= total is at k = min(total, n/2)
+	peak := min(total, n/2)
+	return math.Exp(lnBinomial(n, k) - lnBinomial(n, peak))
+}
+
+func lnBinomial(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
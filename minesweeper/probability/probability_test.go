@@ -0,0 +1,175 @@
+package probability
+
+import (
+	"math"
+	"math/bits"
+	"reflect"
+	"testing"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// bruteForce computes the mine probabilities by going over every placement of the mines left in the undugged cells
+func bruteForce(t *testing.T, cells [][]minesweeper.CellStatus, topology minesweeper.Topology, minesLeft int) [][]float64 {
+	t.Helper()
+	height, width := len(cells), len(cells[0])
+
+	var undugged []minesweeper.Coordinates
+	for row := range cells {
+		for col := range cells[row] {
+			if cells[row][col] == minesweeper.Undugged {
+				undugged = append(undugged, minesweeper.Coordinates{Row: row, Col: col})
+			}
+		}
+	}
+	if len(undugged) > 24 {
+		t.Fatalf("too many undugged cells for brute force: %v", len(undugged))
+	}
+
+	type constraint struct {
+		mask  uint32
+		mines int
+	}
+	var constraints []constraint
+	for row := range cells {
+		for col, status := range cells[row] {
+			mines, isNumber := minesAround(status)
+			if !isNumber {
+				continue
+			}
+			c := constraint{mines: mines}
+			for _, n := range topology.SurroundingCells(width, height, minesweeper.Coordinates{Row: row, Col: col}) {
+				for i, u := range undugged {
+					if u == n {
+						c.mask |= 1 << i
+					}
+				}
+				if isMine(cells[n.Row][n.Col]) {
+					c.mines--
+				}
+			}
+			constraints = append(constraints, c)
+		}
+	}
+
+	counts := make([]float64, len(undugged))
+	var total float64
+	for layout := uint32(0); layout < 1<<len(undugged); layout++ {
+		if bits.OnesCount32(layout) != minesLeft {
+			continue
+		}
+		matches := true
+		for _, c := range constraints {
+			if bits.OnesCount32(layout&c.mask) != c.mines {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		total++
+		for i := range undugged {
+			if layout&(1<<i) != 0 {
+				counts[i]++
+			}
+		}
+	}
+	if total == 0 {
+		t.Fatal("no layout matches the board")
+	}
+
+	res := make([][]float64, height)
+	for row := range res {
+		res[row] = make([]float64, width)
+		for col := range res[row] {
+			if isMine(cells[row][col]) {
+				res[row][col] = 1
+			}
+		}
+	}
+	for i, u := range undugged {
+		res[u.Row][u.Col] = counts[i] / total
+	}
+	return res
+}
+
+func TestCalculateCellsMatchesBruteForce(t *testing.T) {
+	for _, topology := range []minesweeper.Topology{minesweeper.FlatTopology, minesweeper.TorusTopology, minesweeper.HexTopology} {
+		for seed := int64(0); seed < 40; seed++ {
+			f, err := minesweeper.GameGenerator().WithSeed(seed).WithTopology(topology).Custom(5, 4, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Dig(1, 1)
+			if f.GameStatus() != minesweeper.GameOn {
+				continue
+			}
+			if hint, err := minesweeper.GetHint(f); err == nil && hint.Kind == minesweeper.MineHint {
+				// a trusted flag takes part in the numbers around it
+				f.Flag(hint.Cell.Row, hint.Cell.Col)
+			}
+
+			cells := f.AllCellStatus()
+			res, err := CalculateCells(cells, topology, f.FlagsLeft(), DefaultLimits)
+			if err != nil {
+				t.Fatalf("%v seed %v: %v", topology, seed, err)
+			}
+			if !res.Exact {
+				t.Fatalf("%v seed %v: expected an exact result", topology, seed)
+			}
+
+			expected := bruteForce(t, cells, topology, f.FlagsLeft())
+			for row := range expected {
+				for col := range expected[row] {
+					if math.Abs(res.At(row, col)-expected[row][col]) > 1e-9 {
+						t.Fatalf("%v seed %v: (%v, %v) is %v, expected %v", topology, seed, row, col, res.At(row, col), expected[row][col])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCalculateCellsEstimatesOverLimits(t *testing.T) {
+	f, _ := minesweeper.GameGenerator().WithSeed(1).Custom(16, 16, 40)
+	f.Dig(8, 8)
+
+	res, err := CalculateCells(f.AllCellStatus(), f.Topology(), f.FlagsLeft(), Limits{MaxSteps: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Exact {
+		t.Fatal("expected an estimated result")
+	}
+	for row := range res.Cells {
+		for col, p := range res.Cells[row] {
+			if p < 0 || p > 1 || math.IsNaN(p) {
+				t.Fatalf("(%v, %v) is %v", row, col, p)
+			}
+		}
+	}
+}
+
+func TestCalculateCellsErrors(t *testing.T) {
+	u, n1, n0 := minesweeper.Undugged, minesweeper.CellStatus(minesweeper.MinesAround1), minesweeper.CellStatus(minesweeper.NoMinesAround)
+	tests := []struct {
+		name      string
+		cells     [][]minesweeper.CellStatus
+		minesLeft int
+		expected  error
+	}{
+		{"empty board", nil, 0, &InvalidBoardError{}},
+		{"ragged board", [][]minesweeper.CellStatus{{u, u}, {u}}, 1, &InvalidBoardError{}},
+		{"number without room", [][]minesweeper.CellStatus{{n1, n0}}, 1, &InconsistentBoardError{}},
+		{"too few mines left", [][]minesweeper.CellStatus{{n1, u}, {u, u}}, 0, &InconsistentBoardError{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := CalculateCells(test.cells, minesweeper.FlatTopology, test.minesLeft, DefaultLimits)
+			if reflect.TypeOf(err) != reflect.TypeOf(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
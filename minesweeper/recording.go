@@ -0,0 +1,336 @@
+package minesweeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// RecordingVersion is the version of the Recording format, bumped on incompatible changes
+const RecordingVersion = 1
+
+// Recording is a played game, stored as json:
+//
+//	{
+//	  "version": 1,
+//	  "width": 9,
+//	  "height": 9,
+//	  "topology": 1,
+//	  "mines": [{"row": 0, "col": 3}, ...],
+//	  "dug": [...],
+//	  "flagged": [...],
+//	  "moves": [{"at": 1500000000, "action": "dig", "row": 4, "col": 4}, ...],
+//	  "status": 2
+//	}
+//
+// topology is 1 for a torus and 2 for a hex grid, omitted for flat minefields.
+// mines is the layout the game was played on. dug and flagged are the cells that were already dug or flagged
+// when the recording started, they are omitted for games recorded from the start.
+// at is the time of the move since the recording started, in nanoseconds.
+// action is one of dig, flag, unflag, chord, undo and redo (row and col are omitted for undo and redo),
+// or hint for a hint the player asked for, which has no effect on the minefield.
+// status is the GameStatus at the end of the recording - 0 for game on, 1 for lost and 2 for won.
+type Recording struct {
+	Version  int           `json:"version"`
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Topology Topology      `json:"topology,omitempty"`
+	Mines    []Coordinates `json:"mines"`
+	Dug      []Coordinates `json:"dug,omitempty"`
+	Flagged  []Coordinates `json:"flagged,omitempty"`
+	Moves    []Move        `json:"moves"`
+	Status   GameStatus    `json:"status"`
+}
+
+type MoveAction string
+
+const (
+	DigAction    MoveAction = "dig"
+	FlagAction   MoveAction = "flag"
+	UnflagAction MoveAction = "unflag"
+	ChordAction  MoveAction = "chord"
+	UndoAction   MoveAction = "undo"
+	RedoAction   MoveAction = "redo"
+	HintAction   MoveAction = "hint"
+)
+
+type Move struct {
+	At     time.Duration `json:"at"`
+	Action MoveAction    `json:"action"`
+	Row    int           `json:"row,omitempty"`
+	Col    int           `json:"col,omitempty"`
+}
+
+// Recorder is a minefield that records every successful move made on it.
+// Its history starts with the recording, moves made before can't be undone through it, so every recording replays.
+type Recorder interface {
+	Minefield
+	// RecordHint records that the player was given a hint
+	RecordHint(hint Hint)
+	Recording() Recording
+}
+
+type recorder struct {
+	Minefield
+	initial GameState
+	start   time.Time
+	moves   []Move
+	// undoable and redoable count the recorded moves on the history stacks
+	undoable int
+	redoable int
+}
+
+// NewRecorder wraps a minefield and records the moves made on it from now on
+func NewRecorder(f Minefield) Recorder {
+	return &recorder{
+		Minefield: f,
+		initial:   f.State(),
+		start:     time.Now(),
+		moves:     make([]Move, 0),
+	}
+}
+
+func (r *recorder) record(action MoveAction, row, col int, err error) {
+	if err != nil {
+		return
+	}
+	r.moves = append(r.moves, Move{At: time.Since(r.start), Action: action, Row: row, Col: col})
+}
+
+// recordMove records a move, which is kept in the history when it changed the minefield
+func (r *recorder) recordMove(action MoveAction, row, col int, changes ChangeSet, err error) {
+	r.record(action, row, col, err)
+	if err == nil && len(changes) > 0 {
+		r.undoable++
+		r.redoable = 0
+	}
+}
+
+func (r *recorder) Flag(row, col int) (ChangeSet, error) {
+	changes, err := r.Minefield.Flag(row, col)
+	r.recordMove(FlagAction, row, col, changes, err)
+	return changes, err
+}
+
+func (r *recorder) Unflag(row, col int) (ChangeSet, error) {
+	changes, err := r.Minefield.Unflag(row, col)
+	r.recordMove(UnflagAction, row, col, changes, err)
+	return changes, err
+}
+
+func (r *recorder) ToggleFlag(row, col int) (ChangeSet, error) {
+	changes, err := r.Minefield.ToggleFlag(row, col)
+	// record the actual outcome, so replays don't depend on the state before the toggle
+	action := UnflagAction
+	if r.Minefield.CellStatus(row, col) == Flagged {
+		action = FlagAction
+	}
+	r.recordMove(action, row, col, changes, err)
+	return changes, err
+}
+
+func (r *recorder) Dig(row, col int) (ChangeSet, error) {
+	changes, err := r.Minefield.Dig(row, col)
+	r.recordMove(DigAction, row, col, changes, err)
+	return changes, err
+}
+
+func (r *recorder) Chord(row, col int) (ChangeSet, error) {
+	changes, err := r.Minefield.Chord(row, col)
+	r.recordMove(ChordAction, row, col, changes, err)
+	return changes, err
+}
+
+func (r *recorder) Undo() (ChangeSet, error) {
+	if r.undoable == 0 {
+		return nil, &NothingToUndoError{}
+	}
+	changes, err := r.Minefield.Undo()
+	r.record(UndoAction, 0, 0, err)
+	if err == nil {
+		r.undoable--
+		r.redoable++
+	}
+	return changes, err
+}
+
+func (r *recorder) Redo() (ChangeSet, error) {
+	if r.redoable == 0 {
+		return nil, &NothingToRedoError{}
+	}
+	changes, err := r.Minefield.Redo()
+	r.record(RedoAction, 0, 0, err)
+	if err == nil {
+		r.redoable--
+		r.undoable++
+	}
+	return changes, err
+}
+
+func (r *recorder) CanUndo() bool {
+	return r.undoable > 0
+}
+
+func (r *recorder) CanRedo() bool {
+	return r.redoable > 0
+}
+
+func (r *recorder) RecordHint(hint Hint) {
+	r.record(HintAction, hint.Cell.Row, hint.Cell.Col, nil)
+}
+
+func (r *recorder) Recording() Recording {
+	// mines placement may be deferred to the first dig, so the layout is only taken now
+	state := r.Minefield.State()
+	return Recording{
+		Version:  RecordingVersion,
+		Width:    state.Width,
+		Height:   state.Height,
+		Topology: state.Topology,
+		Mines:    state.Mines,
+		Dug:      r.initial.Dug,
+		Flagged:  r.initial.Flagged,
+		Moves:    append([]Move{}, r.moves...),
+		Status:   state.Status,
+	}
+}
+
+// Player re-applies the moves of a recording, one step at a time
+type Player interface {
+	// Field returns the minefield the moves are applied on
+	Field() Minefield
+	// Step applies the next move and returns it with the cells it changed
+	Step() (Move, ChangeSet, error)
+	// Done reports whether all moves were applied
+	Done() bool
+	// Verify applies the remaining moves and checks the game ends with the recorded status
+	Verify() error
+}
+
+type player struct {
+	recording Recording
+	field     Minefield
+	next      int
+}
+
+func NewPlayer(recording Recording) (Player, error) {
+	if recording.Version != RecordingVersion {
+		return nil, fmt.Errorf("unsupported recording version %v", recording.Version)
+	}
+	if len(recording.Mines) == 0 {
+		return nil, fmt.Errorf("recording has no mines")
+	}
+
+	field, err := RestoreMinefield(GameState{
+		Version:   StateVersion,
+		Width:     recording.Width,
+		Height:    recording.Height,
+		Topology:  recording.Topology,
+		MineCount: len(recording.Mines),
+		Mines:     recording.Mines,
+		Dug:       recording.Dug,
+		Flagged:   recording.Flagged,
+		Status:    GameOn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &player{recording: recording, field: field}, nil
+}
+
+func (p *player) Field() Minefield {
+	return p.field
+}
+
+func (p *player) Done() bool {
+	return p.next >= len(p.recording.Moves)
+}
+
+func (p *player) Step() (Move, ChangeSet, error) {
+	if p.Done() {
+		return Move{}, nil, io.EOF
+	}
+
+	move := p.recording.Moves[p.next]
+	p.next++
+
+	var changes ChangeSet
+	var err error
+	switch move.Action {
+	case DigAction:
+		changes, err = p.field.Dig(move.Row, move.Col)
+	case FlagAction:
+		changes, err = p.field.Flag(move.Row, move.Col)
+	case UnflagAction:
+		changes, err = p.field.Unflag(move.Row, move.Col)
+	case ChordAction:
+		changes, err = p.field.Chord(move.Row, move.Col)
+	case UndoAction:
+		changes, err = p.field.Undo()
+	case RedoAction:
+		changes, err = p.field.Redo()
+	case HintAction:
+		// hints don't change the minefield
+	default:
+		err = fmt.Errorf("unknown move action '%v'", move.Action)
+	}
+
+	if err != nil {
+		return move, nil, fmt.Errorf("move %v (%v %v,%v) failed: %w", p.next, move.Action, move.Row, move.Col, err)
+	}
+	return move, changes, nil
+}
+
+func (p *player) Verify() error {
+	for !p.Done() {
+		if _, _, err := p.Step(); err != nil {
+			return err
+		}
+	}
+
+	if status := p.field.GameStatus(); status != p.recording.Status {
+		return &ReplayMismatchError{Expected: p.recording.Status, Actual: status}
+	}
+	return nil
+}
+
+// HintsUsed counts the hints the player was given during the game
+func (r Recording) HintsUsed() int {
+	hints := 0
+	for _, move := range r.Moves {
+		if move.Action == HintAction {
+			hints++
+		}
+	}
+	return hints
+}
+
+// FirstDig returns the cell the game was started from, or nil when the recording started after the first dig
+func (r Recording) FirstDig() *Coordinates {
+	if len(r.Dug) > 0 {
+		return nil
+	}
+	for _, move := range r.Moves {
+		if move.Action == DigAction {
+			return &Coordinates{Row: move.Row, Col: move.Col}
+		}
+	}
+	return nil
+}
+
+// WriteRecording writes the recording as json
+func WriteRecording(w io.Writer, recording Recording) error {
+	recording.Version = RecordingVersion
+	return json.NewEncoder(w).Encode(recording)
+}
+
+// ReadRecording reads a recording written by WriteRecording
+func ReadRecording(r io.Reader) (Recording, error) {
+	var recording Recording
+	if err := json.NewDecoder(r).Decode(&recording); err != nil {
+		return Recording{}, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return recording, nil
+}
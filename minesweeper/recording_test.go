@@ -0,0 +1,214 @@
+package minesweeper
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recordGame plays a won game with every kind of move on the changes test minefield
+func recordGame(t *testing.T) (Recorder, Minefield) {
+	t.Helper()
+	f := newChangesTestMinefield(t)
+	r := NewRecorder(f)
+	moves := []func() (ChangeSet, error){
+		func() (ChangeSet, error) { return r.Dig(0, 0) },
+		func() (ChangeSet, error) { return r.ToggleFlag(2, 5) },
+		func() (ChangeSet, error) { return r.Unflag(2, 5) },
+		func() (ChangeSet, error) { return r.Flag(1, 5) },
+		func() (ChangeSet, error) { return r.Undo() },
+		func() (ChangeSet, error) { return r.Redo() },
+		func() (ChangeSet, error) { return r.Flag(0, 5) },
+		func() (ChangeSet, error) { return r.Chord(1, 4) },
+		func() (ChangeSet, error) { return r.Dig(3, 5) },
+	}
+	for i, move := range moves {
+		if i == 3 {
+			r.RecordHint(Hint{Kind: MineHint, Cell: Coordinates{Row: 1, Col: 5}, Exact: true})
+		}
+		if _, err := move(); err != nil {
+			t.Fatalf("move %v: %v", i, err)
+		}
+	}
+	// failed moves are not recorded
+	if _, err := r.Dig(0, 0); err == nil {
+		t.Fatal("digging after the game is over should fail")
+	}
+	if r.GameStatus() != Won {
+		t.Fatalf("game status is %v at the end of the recording", r.GameStatus())
+	}
+	return r, f
+}
+
+func TestRecordingReplay(t *testing.T) {
+	r, f := recordGame(t)
+
+	var buf bytes.Buffer
+	if err := WriteRecording(&buf, r.Recording()); err != nil {
+		t.Fatal(err)
+	}
+	recording, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// empty cell lists are omitted from the json
+	expected := r.Recording()
+	expected.Dug, expected.Flagged = nil, nil
+	if !reflect.DeepEqual(recording, expected) {
+		t.Fatalf("read recording is %+v, expected %+v", recording, expected)
+	}
+	if len(recording.Moves) != 10 || recording.HintsUsed() != 1 {
+		t.Errorf("recorded %v moves and %v hints, expected 10 moves and 1 hint", len(recording.Moves), recording.HintsUsed())
+	}
+	if first := recording.FirstDig(); first == nil || *first != (Coordinates{Row: 0, Col: 0}) {
+		t.Errorf("first dig is %v, expected (0, 0)", first)
+	}
+
+	player, err := NewPlayer(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(player.Field().AllCellStatus(), f.AllCellStatus()) {
+		t.Error("the replayed minefield differs from the recorded one")
+	}
+}
+
+func TestRecordingStep(t *testing.T) {
+	r, _ := recordGame(t)
+	player, err := NewPlayer(r.Recording())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first dig floods the board, the hint changes nothing
+	move, changes, err := player.Step()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move.Action != DigAction || len(changes) < 2 {
+		t.Errorf("first step is %v changing %v cells, expected a flood", move.Action, len(changes))
+	}
+	for !player.Done() {
+		move, changes, err := player.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if move.Action == HintAction && len(changes) > 0 {
+			t.Errorf("a hint changed %v cells", len(changes))
+		}
+	}
+}
+
+func TestRecordingVerifyMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(recording *Recording)
+	}{
+		{"status", func(recording *Recording) {
+			recording.Status = Lost
+		}},
+		{"move", func(recording *Recording) {
+			// the winning dig moved to a mine
+			recording.Moves[len(recording.Moves)-1] = Move{Action: DigAction, Row: 4, Col: 0}
+		}},
+		{"missing move", func(recording *Recording) {
+			recording.Moves = recording.Moves[:len(recording.Moves)-1]
+		}},
+		{"failing move", func(recording *Recording) {
+			recording.Moves = append(recording.Moves, Move{Action: DigAction, Row: 0, Col: 0})
+		}},
+		{"unknown action", func(recording *Recording) {
+			recording.Moves[0].Action = "jump"
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, _ := recordGame(t)
+			recording := r.Recording()
+			test.tamper(&recording)
+
+			player, err := NewPlayer(recording)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := player.Verify(); err == nil {
+				t.Error("a tampered recording was verified")
+			}
+		})
+	}
+
+	r, _ := recordGame(t)
+	recording := r.Recording()
+	recording.Status = GameOn
+	player, err := NewPlayer(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mismatch *ReplayMismatchError
+	if err := player.Verify(); !errors.As(err, &mismatch) || mismatch.Expected != GameOn || mismatch.Actual != Won {
+		t.Errorf("expected a replay mismatch from game on to won, got %v", err)
+	}
+}
+
+func TestRecordingUndoFromMidGame(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	if _, err := f.Dig(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Flag(0, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	// the moves made before the recording started can't be undone through it
+	r := NewRecorder(f)
+	if r.CanUndo() {
+		t.Error("a new recorder can undo")
+	}
+	if _, err := r.Undo(); !errors.As(err, new(*NothingToUndoError)) {
+		t.Errorf("expected nothing to undo, got %v", err)
+	}
+
+	if _, err := r.Dig(2, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Undo(); !errors.As(err, new(*NothingToUndoError)) {
+		t.Errorf("expected nothing to undo past the recording start, got %v", err)
+	}
+	if _, err := r.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Redo(); !errors.As(err, new(*NothingToRedoError)) {
+		t.Errorf("expected nothing to redo, got %v", err)
+	}
+	if _, err := r.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Dig(2, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Dig(3, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	recording := r.Recording()
+	if recording.FirstDig() != nil {
+		t.Errorf("a recording started mid-game has a first dig %v", recording.FirstDig())
+	}
+	player, err := NewPlayer(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(player.Field().AllCellStatus(), f.AllCellStatus()) {
+		t.Error("the replayed minefield differs from the recorded one")
+	}
+}
@@ -0,0 +1,207 @@
+package minesweeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// StateVersion is the version of the GameState format, bumped on incompatible changes
+const StateVersion = 1
+
+// GameState is a serializable snapshot of a game
+type GameState struct {
+	Version   int `json:"version"`
+	Width     int `json:"width"`
+	Height    int `json:"height"`
+	MineCount int `json:"mineCount"`
+	// Mines is empty while the mines placement is deferred to the first dig
+	Mines      []Coordinates    `json:"mines"`
+	Dug        []Coordinates    `json:"dug"`
+	Flagged    []Coordinates    `json:"flagged"`
+	Status     GameStatus       `json:"status"`
+	Seed       int64            `json:"seed"`
+	FirstClick FirstClickPolicy `json:"firstClick"`
+	Topology   Topology         `json:"topology,omitempty"`
+	NoGuess    *NoGuessLimits   `json:"noGuess,omitempty"`
+	// Elapsed is the play time measured by the frontend, the minefield doesn't keep time
+	Elapsed time.Duration `json:"elapsed"`
+	// Assistance is tracked by the frontend
+	Assistance
+	// Clicks are counted by StatsTracker, zero for minefields that are not tracked
+	Clicks Clicks `json:"clicks"`
+}
+
+// Assistance - the help the player got during a game. Assisted games don't count toward records.
+type Assistance struct {
+	HintsUsed   int  `json:"hintsUsed,omitempty"`
+	HeatmapUsed bool `json:"heatmapUsed,omitempty"`
+	SolverUsed  bool `json:"solverUsed,omitempty"`
+}
+
+func (a Assistance) Assisted() bool {
+	return a.HintsUsed > 0 || a.HeatmapUsed || a.SolverUsed
+}
+
+func (f *minefield) State() GameState {
+	state := GameState{
+		Version:    StateVersion,
+		Width:      f.width,
+		Height:     f.height,
+		MineCount:  f.mineCount,
+		Mines:      slices.SortedFunc(slices.Values(f.mines), compareCoordinates),
+		Dug:        make([]Coordinates, 0, f.dugCount),
+		Flagged:    make([]Coordinates, 0, len(f.flags)),
+		Status:     f.status,
+		Seed:       f.seed,
+		FirstClick: f.firstClick,
+		Topology:   f.topology,
+		NoGuess:    f.noGuess,
+	}
+
+	for i, c := range f.cells {
+		if c.isDug {
+			state.Dug = append(state.Dug, f.coordinates(i))
+		}
+		if c.isFlagged {
+			state.Flagged = append(state.Flagged, f.coordinates(i))
+		}
+	}
+	return state
+}
+
+// RestoreMinefield recreates a working minefield from a snapshot. The moves history is not restored.
+func RestoreMinefield(state GameState) (Minefield, error) {
+	if state.Version != StateVersion {
+		return nil, &InvalidGameStateError{Reason: fmt.Sprintf("unsupported version %v", state.Version)}
+	}
+	if err := instance.validate(state.Width, state.Height, state.MineCount); err != nil {
+		return nil, err
+	}
+	if !state.Topology.isValid() {
+		return nil, &InvalidGameStateError{Reason: fmt.Sprintf("unknown topology %v", int(state.Topology))}
+	}
+	if len(state.Mines) != 0 && len(state.Mines) != state.MineCount {
+		return nil, &InvalidGameStateError{Reason: fmt.Sprintf("expected %v mines but found %v", state.MineCount, len(state.Mines))}
+	}
+
+	f := newEmptyMinefield(state.Width, state.Height, state.Topology, state.MineCount)
+	f.seed = state.Seed
+	f.firstClick = state.FirstClick
+	f.noGuess = state.NoGuess
+	f.status = state.Status
+
+	if len(state.Mines) == 0 {
+		if len(state.Dug) > 0 || state.Status != GameOn {
+			// the mines are placed by the first dig
+			return nil, &InvalidGameStateError{Reason: "cells are dug before the mines were placed"}
+		}
+		f.layout = newLayout(state.Width, state.Height, state.Topology, state.MineCount, state.FirstClick, state.NoGuess, state.Seed)
+		if f.layout == nil {
+			return nil, &InvalidGameStateError{Reason: "no mines"}
+		}
+	} else {
+		mines := NewMineList(state.Width, state.Height, state.MineCount)
+		for _, coord := range state.Mines {
+			if err := mines.Add(coord.Row, coord.Col); err != nil {
+				return nil, err
+			}
+		}
+		if mines.Len() != state.MineCount {
+			return nil, &InvalidGameStateError{Reason: "duplicate mines"}
+		}
+		f.placeMines(mines)
+	}
+
+	for _, coord := range state.Flagged {
+		exists, c := f.getCell(coord.Row, coord.Col)
+		if !exists {
+			return nil, &InvalidCoordinatesError{}
+		}
+		if !c.isFlagged {
+			c.isFlagged = true
+			f.flags[coord] = struct{}{}
+		}
+	}
+	for _, coord := range state.Dug {
+		exists, c := f.getCell(coord.Row, coord.Col)
+		if !exists {
+			return nil, &InvalidCoordinatesError{}
+		}
+		if c.isFlagged {
+			return nil, &InvalidGameStateError{Reason: fmt.Sprintf("cell (%v, %v) is both dug and flagged", coord.Row, coord.Col)}
+		}
+		if !c.isDug {
+			c.isDug = true
+			f.dugCount++
+		}
+	}
+	if len(f.flags) > f.mineCount {
+		return nil, &OutOfFlagsError{}
+	}
+	if err := f.validateStatus(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// validateStatus checks the game status of a restored minefield matches its dug cells
+func (f *minefield) validateStatus() error {
+	minesDug := 0
+	for _, c := range f.cells {
+		if c.isDug && c.isMine {
+			minesDug++
+		}
+	}
+	allSafeDug := f.dugCount-minesDug == f.width*f.height-f.mineCount
+
+	switch f.status {
+	case GameOn:
+		if minesDug > 0 {
+			return &InvalidGameStateError{Reason: "a mine is dug, but the game is on"}
+		}
+		if allSafeDug && f.layout == nil {
+			return &InvalidGameStateError{Reason: "every safe cell is dug, but the game is on"}
+		}
+	case Lost:
+		if minesDug == 0 {
+			return &InvalidGameStateError{Reason: "the game is lost, but no mine is dug"}
+		}
+	case Won:
+		if minesDug > 0 {
+			return &InvalidGameStateError{Reason: "the game is won, but a mine is dug"}
+		}
+		if !allSafeDug {
+			return &InvalidGameStateError{Reason: "the game is won, but not every safe cell is dug"}
+		}
+	default:
+		return &InvalidGameStateError{Reason: fmt.Sprintf("unknown game status %v", int(f.status))}
+	}
+	return nil
+}
+
+// SaveGame writes the game state as json
+func SaveGame(w io.Writer, state GameState) error {
+	state.Version = StateVersion
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(state)
+}
+
+// LoadGame reads a game state written by SaveGame and recreates its minefield.
+// The state is returned as well, for the fields kept by the frontend.
+func LoadGame(r io.Reader) (Minefield, GameState, error) {
+	var state GameState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, state, fmt.Errorf("failed to read game state: %w", err)
+	}
+
+	f, err := RestoreMinefield(state)
+	if err != nil {
+		return nil, state, err
+	}
+	return f, state, nil
+}
@@ -0,0 +1,144 @@
+package minesweeper
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// checkStateRoundTrip saves a game, loads it back, and verifies the loaded game has the same state
+func checkStateRoundTrip(t *testing.T, f Minefield) Minefield {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := SaveGame(&buf, f.State()); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _, err := LoadGame(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.State(), f.State()) {
+		t.Errorf("loaded state is %+v, expected %+v", loaded.State(), f.State())
+	}
+	if !reflect.DeepEqual(loaded.AllCellStatus(), f.AllCellStatus()) {
+		t.Error("the loaded minefield differs from the saved one")
+	}
+	return loaded
+}
+
+func TestSaveRoundTripMidGame(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	if _, err := f.Dig(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Flag(0, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := checkStateRoundTrip(t, f)
+	// the loaded game goes on
+	if _, err := loaded.Dig(2, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.Dig(3, 5); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.GameStatus() != Won {
+		t.Errorf("game status is %v after digging every safe cell", loaded.GameStatus())
+	}
+}
+
+func TestSaveRoundTripLost(t *testing.T) {
+	f := newChangesTestMinefield(t)
+	if _, err := f.Flag(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Dig(4, 0); err != nil {
+		t.Fatal(err)
+	}
+	checkStateRoundTrip(t, f)
+}
+
+func TestSaveRoundTripDeferred(t *testing.T) {
+	for _, topology := range topologies {
+		t.Run(topology.String(), func(t *testing.T) {
+			f, err := GameGenerator().WithSeed(5).WithTopology(topology).WithFirstClickPolicy(FirstClickOpening).
+				Custom(9, 9, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := f.Flag(8, 8); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := checkStateRoundTrip(t, f)
+			// the loaded game places the same mines on the first dig
+			if _, err := f.Dig(4, 4); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loaded.Dig(4, 4); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(loaded.State(), f.State()) {
+				t.Errorf("loaded state is %+v after the first dig, expected %+v", loaded.State(), f.State())
+			}
+		})
+	}
+}
+
+func TestRestoreMinefieldErrors(t *testing.T) {
+	// mines at (0, 5), (1, 5), (4, 0) and (4, 5)
+	valid := newChangesTestMinefield(t).State()
+
+	tests := []struct {
+		name   string
+		modify func(state *GameState)
+	}{
+		{"dug and flagged", func(state *GameState) {
+			state.Dug = []Coordinates{{0, 0}}
+			state.Flagged = []Coordinates{{0, 0}}
+		}},
+		{"mine dug while on", func(state *GameState) {
+			state.Dug = []Coordinates{{0, 5}}
+		}},
+		{"lost without a dug mine", func(state *GameState) {
+			state.Status = Lost
+		}},
+		{"won with undugged cells", func(state *GameState) {
+			state.Status = Won
+		}},
+		{"unknown status", func(state *GameState) {
+			state.Status = 7
+		}},
+		{"dug before the mines were placed", func(state *GameState) {
+			state.Mines = nil
+			state.FirstClick = FirstClickSafe
+			state.Dug = []Coordinates{{0, 0}}
+		}},
+		{"lost before the mines were placed", func(state *GameState) {
+			state.Mines = nil
+			state.FirstClick = FirstClickSafe
+			state.Status = Lost
+		}},
+		{"mines count mismatch", func(state *GameState) {
+			state.MineCount = 5
+		}},
+		{"unknown topology", func(state *GameState) {
+			state.Topology = 7
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := valid
+			state.Mines = append([]Coordinates(nil), valid.Mines...)
+			test.modify(&state)
+
+			_, err := RestoreMinefield(state)
+			var stateErr *InvalidGameStateError
+			if !errors.As(err, &stateErr) {
+				t.Errorf("expected an invalid game state error, got %v", err)
+			}
+		})
+	}
+}
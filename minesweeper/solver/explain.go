@@ -0,0 +1,108 @@
+package solver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// Explain describes a deduction in words, on the minefield it was deduced from
+func Explain(f minesweeper.Minefield, d Deduction) string {
+	conclusion := fmt.Sprintf("%v is safe", coordString(d.Cell))
+	if d.Mine {
+		conclusion = fmt.Sprintf("%v is a mine", coordString(d.Cell))
+	}
+
+	switch d.Rule {
+	case SingleCell:
+		if len(d.Sources) == 0 {
+			break
+		}
+		n := neighborhoodOf(f, d.Sources[0])
+		if !d.Mine {
+			flags := "flag"
+			if len(n.flags) > 1 {
+				flags = "flags"
+			}
+			return fmt.Sprintf("the %v is satisfied by the %v at %v, so %v", n, flags, coordsString(n.flags), conclusion)
+		}
+		if len(n.flags) > 0 {
+			return fmt.Sprintf("the %v has %v and %v around it, so %v",
+				n, plural(len(n.flags), "flag"), plural(len(n.undugged), "undugged cell"), conclusion)
+		}
+		return fmt.Sprintf("the %v has only %v around it, so %v", n, plural(len(n.undugged), "undugged cell"), conclusion)
+	case Subset:
+		if len(d.Sources) < 2 {
+			break
+		}
+		inner, outer := neighborhoodOf(f, d.Sources[0]), neighborhoodOf(f, d.Sources[1])
+		reason := fmt.Sprintf("the %v needs %v among cells that are all around the %v too",
+			inner, plural(inner.missing(), "mine"), outer)
+		restMines := outer.missing() - inner.missing()
+		if !d.Mine {
+			return fmt.Sprintf("%v, which needs %v, so its other cells have no mines and %v",
+				reason, plural(outer.missing(), "mine"), conclusion)
+		}
+		rest := fmt.Sprintf("its other %v are mines", plural(restMines, "cell"))
+		if restMines == 1 {
+			rest = "its other cell is a mine"
+		}
+		return fmt.Sprintf("%v, which needs %v, so %v and %v", reason, plural(outer.missing(), "mine"), rest, conclusion)
+	case GlobalCount:
+		if !d.Mine {
+			return fmt.Sprintf("all mines are flagged, so %v", conclusion)
+		}
+		return fmt.Sprintf("the %v left fill all undugged cells, so %v", plural(f.FlagsLeft(), "mine"), conclusion)
+	}
+	return conclusion
+}
+
+// neighborhood - a numbered cell and its surroundings
+type neighborhood struct {
+	cell     minesweeper.Coordinates
+	mines    int
+	flags    []minesweeper.Coordinates
+	undugged []minesweeper.Coordinates
+}
+
+func neighborhoodOf(f minesweeper.Minefield, cell minesweeper.Coordinates) neighborhood {
+	n := neighborhood{cell: cell, mines: int(f.CellStatus(cell.Row, cell.Col))}
+	for _, c := range f.Topology().SurroundingCells(f.Width(), f.Height(), cell) {
+		switch f.CellStatus(c.Row, c.Col) {
+		case minesweeper.Undugged:
+			n.undugged = append(n.undugged, c)
+		case minesweeper.Flagged:
+			n.flags = append(n.flags, c)
+		}
+	}
+	return n
+}
+
+// missing returns the mines around the cell that are not flagged yet
+func (n neighborhood) missing() int {
+	return n.mines - len(n.flags)
+}
+
+func (n neighborhood) String() string {
+	return fmt.Sprintf("%v at %v", n.mines, coordString(n.cell))
+}
+
+func coordString(c minesweeper.Coordinates) string {
+	return fmt.Sprintf("(%v, %v)", c.Row, c.Col)
+}
+
+func coordsString(coords []minesweeper.Coordinates) string {
+	strs := make([]string, len(coords))
+	for i, c := range coords {
+		strs[i] = coordString(c)
+	}
+	return strings.Join(strs, ", ")
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %v", noun)
+	}
+	return fmt.Sprintf("%v %vs", count, noun)
+}
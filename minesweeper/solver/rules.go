@@ -0,0 +1,82 @@
+// Package solver finds the forced moves of a minefield using standard deduction rules,
+// and plays them until the game is over or a guess is needed.
+package solver
+
+import (
+	"fmt"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper/internal/deduce"
+)
+
+// Rule - the deduction rule a forced move was found by
+type Rule = deduce.Rule
+
+const (
+	// SingleCell - a number whose missing mines are zero, or equal to its undugged surroundings
+	SingleCell = deduce.SingleCell
+	// Subset - a number whose undugged surroundings contain the undugged surroundings of another number,
+	// so the cells only it touches hold the difference between their missing mines
+	Subset = deduce.Subset
+	// GlobalCount - the mines left are zero, or equal to the undugged cells
+	GlobalCount = deduce.GlobalCount
+)
+
+// Deduction - a cell that is certainly safe or certainly a mine, and why
+type Deduction struct {
+	Cell minesweeper.Coordinates
+	Mine bool
+	Rule Rule
+	// Sources are the numbered cells the deduction is based on, empty for GlobalCount
+	Sources []minesweeper.Coordinates
+}
+
+func (d Deduction) String() string {
+	what := "safe"
+	if d.Mine {
+		what = "a mine"
+	}
+	s := fmt.Sprintf("(%v, %v) is %v by %v", d.Cell.Row, d.Cell.Col, what, d.Rule)
+	for i, src := range d.Sources {
+		if i == 0 {
+			s += " of"
+		}
+		s += fmt.Sprintf(" (%v, %v)", src.Row, src.Col)
+	}
+	return s
+}
+
+// Deduce returns the forced moves of the minefield, each cell once with the first rule that found it.
+// Flags are trusted to be mines, flagged cells are not returned.
+func Deduce(f minesweeper.Minefield) []Deduction {
+	if f.GameStatus() != minesweeper.GameOn {
+		return nil
+	}
+
+	findings := deduce.Deduce(minesweeper.DeductionBoard(f))
+	res := make([]Deduction, len(findings))
+	for i, finding := range findings {
+		res[i] = Deduction{
+			Cell:    coordinatesOf(f, finding.Cell),
+			Mine:    finding.Mine,
+			Rule:    finding.Rule,
+			Sources: coordinatesOfAll(f, finding.Sources),
+		}
+	}
+	return res
+}
+
+func coordinatesOf(f minesweeper.Minefield, index int) minesweeper.Coordinates {
+	return minesweeper.Coordinates{Row: index / f.Width(), Col: index % f.Width()}
+}
+
+func coordinatesOfAll(f minesweeper.Minefield, indexes []int) []minesweeper.Coordinates {
+	if len(indexes) == 0 {
+		return nil
+	}
+	res := make([]minesweeper.Coordinates, len(indexes))
+	for i, index := range indexes {
+		res[i] = coordinatesOf(f, index)
+	}
+	return res
+}
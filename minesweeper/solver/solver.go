@@ -0,0 +1,92 @@
+package solver
+
+import (
+	"github.com/HuBeZa/minesweeper/minesweeper"
+	"github.com/HuBeZa/minesweeper/minesweeper/internal/deduce"
+)
+
+// Report - the moves a solve played and where it ended
+type Report struct {
+	// Moves are the deductions played, in order
+	Moves  []Deduction
+	Status minesweeper.GameStatus
+	// Stuck is set when the game is still on but there are no forced moves, the next move is a guess
+	Stuck bool
+	// Frontier are the undugged cells bordering numbers when stuck, the candidates for the guess
+	Frontier []minesweeper.Coordinates
+}
+
+// Step plays the forced moves of the minefield once - flags the mines, then digs the safe cells.
+// It returns the deductions played, none if the minefield is stuck or the game is over.
+func Step(f minesweeper.Minefield) ([]Deduction, error) {
+	deductions := Deduce(f)
+	played := make([]Deduction, 0, len(deductions))
+
+	for _, mine := range []bool{true, false} {
+		for _, d := range deductions {
+			if d.Mine != mine || f.GameStatus() != minesweeper.GameOn {
+				continue
+			}
+			// an earlier dig may have opened the cell already
+			if f.CellStatus(d.Cell.Row, d.Cell.Col) != minesweeper.Undugged {
+				continue
+			}
+
+			if err := Apply(f, d); err != nil {
+				return played, err
+			}
+			played = append(played, d)
+		}
+	}
+	return played, nil
+}
+
+// Apply plays a single deduction - flags a mine or digs a safe cell
+func Apply(f minesweeper.Minefield, d Deduction) error {
+	var err error
+	if d.Mine {
+		_, err = f.Flag(d.Cell.Row, d.Cell.Col)
+	} else {
+		_, err = f.Dig(d.Cell.Row, d.Cell.Col)
+	}
+	return err
+}
+
+// Solve plays forced moves until the game is over or the minefield is stuck.
+// The game must be started by a dig, a minefield without any dug cell is stuck.
+// Wrong flags placed before solving may lead to a lost game.
+func Solve(f minesweeper.Minefield) (Report, error) {
+	report := Report{Moves: make([]Deduction, 0)}
+	for f.GameStatus() == minesweeper.GameOn {
+		played, err := Step(f)
+		report.Moves = append(report.Moves, played...)
+		if err != nil {
+			report.Status = f.GameStatus()
+			return report, err
+		}
+		if len(played) == 0 {
+			report.Stuck = true
+			report.Frontier = Frontier(f)
+			break
+		}
+	}
+
+	report.Status = f.GameStatus()
+	return report, nil
+}
+
+// Frontier returns the undugged cells bordering numbers
+func Frontier(f minesweeper.Minefield) []minesweeper.Coordinates {
+	constraints, _ := deduce.Constraints(minesweeper.DeductionBoard(f))
+	seen := make(map[int]struct{})
+	res := make([]minesweeper.Coordinates, 0)
+	for _, c := range constraints {
+		for _, cell := range c.Cells {
+			if _, found := seen[cell]; !found {
+				seen[cell] = struct{}{}
+				res = append(res, coordinatesOf(f, cell))
+			}
+		}
+	}
+	return res
+}
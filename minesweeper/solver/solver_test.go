@@ -0,0 +1,228 @@
+package solver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HuBeZa/minesweeper/minesweeper"
+)
+
+// fieldOf restores a minefield from rows of cells: '*' is a mine, 'F' a flagged mine, 'o' a dug cell,
+// and '.' an undugged safe cell
+func fieldOf(t *testing.T, rows ...string) minesweeper.Minefield {
+	t.Helper()
+	state := minesweeper.GameState{
+		Version: minesweeper.StateVersion,
+		Width:   len(rows[0]),
+		Height:  len(rows),
+		Status:  minesweeper.GameOn,
+	}
+	for row, cells := range rows {
+		for col, cell := range cells {
+			coord := minesweeper.Coordinates{Row: row, Col: col}
+			switch cell {
+			case 'F':
+				state.Flagged = append(state.Flagged, coord)
+				fallthrough
+			case '*':
+				state.Mines = append(state.Mines, coord)
+			case 'o':
+				state.Dug = append(state.Dug, coord)
+			}
+		}
+	}
+	state.MineCount = len(state.Mines)
+
+	f, err := minesweeper.RestoreMinefield(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func coords(rowCols ...int) []minesweeper.Coordinates {
+	res := make([]minesweeper.Coordinates, 0, len(rowCols)/2)
+	for i := 0; i < len(rowCols); i += 2 {
+		res = append(res, minesweeper.Coordinates{Row: rowCols[i], Col: rowCols[i+1]})
+	}
+	return res
+}
+
+// oneTwoOne is the 1-2-1 pattern - both mines are found by subsets, then the 1s are satisfied
+var oneTwoOne = []string{
+	"ooo",
+	"*.*",
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     []string
+		expected Report
+	}{
+		{
+			name: "solved",
+			rows: oneTwoOne,
+			expected: Report{
+				Moves: []Deduction{
+					{Cell: coords(1, 2)[0], Mine: true, Rule: Subset, Sources: coords(0, 0, 0, 1)},
+					{Cell: coords(1, 0)[0], Mine: true, Rule: Subset, Sources: coords(0, 2, 0, 1)},
+					{Cell: coords(1, 1)[0], Rule: SingleCell, Sources: coords(0, 0)},
+				},
+				Status: minesweeper.Won,
+			},
+		},
+		{
+			name: "stuck",
+			// the 2 at (1, 1) has one mine left for two cells
+			rows: []string{
+				"oF**",
+				"oo..",
+			},
+			expected: Report{
+				Moves:    []Deduction{},
+				Status:   minesweeper.GameOn,
+				Stuck:    true,
+				Frontier: coords(0, 2, 1, 2),
+			},
+		},
+		{
+			name: "stuck after moves",
+			// the 1 at (1, 3) is dug by the subset, and has one mine left for two cells
+			rows: []string{
+				"o*oo.",
+				"ooo.*",
+			},
+			expected: Report{
+				Moves: []Deduction{
+					{Cell: coords(0, 1)[0], Mine: true, Rule: SingleCell, Sources: coords(0, 0)},
+					{Cell: coords(1, 3)[0], Rule: Subset, Sources: coords(0, 0, 0, 2)},
+				},
+				Status:   minesweeper.GameOn,
+				Stuck:    true,
+				Frontier: coords(0, 4, 1, 4),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := Solve(fieldOf(t, test.rows...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(report, test.expected) {
+				t.Errorf("report is %+v, expected %+v", report, test.expected)
+			}
+		})
+	}
+}
+
+func TestStep(t *testing.T) {
+	f := fieldOf(t, oneTwoOne...)
+	expected := [][]minesweeper.Coordinates{
+		// the mines are flagged first
+		coords(1, 2, 1, 0),
+		coords(1, 1),
+		nil,
+	}
+	for i, cells := range expected {
+		played, err := Step(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []minesweeper.Coordinates
+		for _, d := range played {
+			got = append(got, d.Cell)
+		}
+		if !reflect.DeepEqual(got, cells) {
+			t.Errorf("step %v played %v, expected %v", i, got, cells)
+		}
+	}
+	if f.GameStatus() != minesweeper.Won {
+		t.Errorf("game status is %v after the steps", f.GameStatus())
+	}
+	if f.CellStatus(1, 0) != minesweeper.Flagged || f.CellStatus(1, 2) != minesweeper.Flagged {
+		t.Error("the mines were not flagged")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     []string
+		expected []string
+	}{
+		{
+			name: "satisfied by a flag",
+			rows: []string{
+				"Fo.",
+				"oo.",
+			},
+			expected: []string{
+				"the 1 at (0, 1) is satisfied by the flag at (0, 0), so (0, 2) is safe",
+				"the 1 at (0, 1) is satisfied by the flag at (0, 0), so (1, 2) is safe",
+			},
+		},
+		{
+			name: "subset safe",
+			rows: []string{
+				"oo*.",
+				"ooo.",
+			},
+			expected: []string{
+				"the 1 at (0, 1) has only 1 undugged cell around it, so (0, 2) is a mine",
+				"the 1 at (0, 1) needs 1 mine among cells that are all around the 1 at (1, 2) too, which needs 1 mine, " +
+					"so its other cells have no mines and (0, 3) is safe",
+				"the 1 at (0, 1) needs 1 mine among cells that are all around the 1 at (1, 2) too, which needs 1 mine, " +
+					"so its other cells have no mines and (1, 3) is safe",
+			},
+		},
+		{
+			name: "flags and undugged cells",
+			rows: []string{
+				"Fo*.",
+				"ooo.",
+			},
+			expected: []string{
+				"the 2 at (0, 1) has 1 flag and 1 undugged cell around it, so (0, 2) is a mine",
+				"the 2 at (0, 1) needs 1 mine among cells that are all around the 1 at (1, 2) too, which needs 1 mine, " +
+					"so its other cells have no mines and (0, 3) is safe",
+				"the 2 at (0, 1) needs 1 mine among cells that are all around the 1 at (1, 2) too, which needs 1 mine, " +
+					"so its other cells have no mines and (1, 3) is safe",
+			},
+		},
+		{
+			name: "subset mine",
+			rows: oneTwoOne,
+			expected: []string{
+				"the 1 at (0, 0) needs 1 mine among cells that are all around the 2 at (0, 1) too, which needs 2 mines, " +
+					"so its other cell is a mine and (1, 2) is a mine",
+				"the 1 at (0, 2) needs 1 mine among cells that are all around the 2 at (0, 1) too, which needs 2 mines, " +
+					"so its other cell is a mine and (1, 0) is a mine",
+			},
+		},
+		{
+			name: "global count",
+			rows: []string{
+				"Foo.",
+				"ooo.",
+			},
+			expected: []string{
+				"all mines are flagged, so (0, 3) is safe",
+				"all mines are flagged, so (1, 3) is safe",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := fieldOf(t, test.rows...)
+			var got []string
+			for _, d := range Deduce(f) {
+				got = append(got, Explain(f, d))
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("explanations are\n%q\nexpected\n%q", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package minesweeper
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Clicks counts the moves of a game by mouse button. Wasted clicks are the moves that changed nothing.
+type Clicks struct {
+	Left   int `json:"left"`
+	Right  int `json:"right"`
+	Chord  int `json:"chord"`
+	Wasted int `json:"wasted"`
+}
+
+func (c Clicks) Total() int {
+	return c.Left + c.Right + c.Chord
+}
+
+// Stats - the clicks of a game and the performance measures computed from them
+type Stats struct {
+	Clicks
+	Elapsed time.Duration
+	// BBBV is the 3BV of the layout and SolvedBBBV the part of it that was dug, they are equal for won games
+	BBBV       int
+	SolvedBBBV int
+	// BBBVPerSecond is the solved 3BV per second
+	BBBVPerSecond float64
+	// IOE (index of efficiency) is the solved 3BV per click
+	IOE float64
+	// RQP (rapidity, quality, performance) is the time divided by the 3BV per second, lower is better
+	RQP float64
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("clicks: %v (%v wasted) • 3BV/s: %.2f • IOE: %.2f • RQP: %.1f",
+		s.Total(), s.Wasted, s.BBBVPerSecond, s.IOE, s.RQP)
+}
+
+// StatsTracker is a minefield that counts the clicks made on it
+type StatsTracker interface {
+	Minefield
+	Clicks() Clicks
+	// Stats computes the performance measures of the game so far, given the time played
+	Stats(elapsed time.Duration) Stats
+}
+
+type statsTracker struct {
+	Minefield
+	clicks Clicks
+	// bbbvCells are the cells whose digging does a 3BV click, found once the mines are placed
+	bbbvCells []Coordinates
+}
+
+// NewStatsTracker wraps a minefield and counts the clicks made on it from now on, adding to the clicks of a resumed game
+func NewStatsTracker(f Minefield, clicks Clicks) StatsTracker {
+	return &statsTracker{
+		Minefield: f,
+		clicks:    clicks,
+	}
+}
+
+// count counts a click, clicks on a finished game are not part of it
+func (s *statsTracker) count(button *int, changes ChangeSet, err error) {
+	var gameOver *GameOverError
+	if errors.As(err, &gameOver) {
+		return
+	}
+
+	*button++
+	if err != nil || len(changes) == 0 {
+		s.clicks.Wasted++
+	}
+}
+
+func (s *statsTracker) Flag(row, col int) (ChangeSet, error) {
+	changes, err := s.Minefield.Flag(row, col)
+	s.count(&s.clicks.Right, changes, err)
+	return changes, err
+}
+
+func (s *statsTracker) Unflag(row, col int) (ChangeSet, error) {
+	changes, err := s.Minefield.Unflag(row, col)
+	s.count(&s.clicks.Right, changes, err)
+	return changes, err
+}
+
+func (s *statsTracker) ToggleFlag(row, col int) (ChangeSet, error) {
+	changes, err := s.Minefield.ToggleFlag(row, col)
+	s.count(&s.clicks.Right, changes, err)
+	return changes, err
+}
+
+func (s *statsTracker) Dig(row, col int) (ChangeSet, error) {
+	changes, err := s.Minefield.Dig(row, col)
+	s.count(&s.clicks.Left, changes, err)
+	return changes, err
+}
+
+func (s *statsTracker) Chord(row, col int) (ChangeSet, error) {
+	changes, err := s.Minefield.Chord(row, col)
+	s.count(&s.clicks.Chord, changes, err)
+	return changes, err
+}
+
+func (s *statsTracker) Clicks() Clicks {
+	return s.clicks
+}
+
+// State returns the snapshot of the minefield, with the clicks so far
+func (s *statsTracker) State() GameState {
+	state := s.Minefield.State()
+	state.Clicks = s.clicks
+	return state
+}
+
+func (s *statsTracker) Stats(elapsed time.Duration) Stats {
+	stats := Stats{Clicks: s.clicks, Elapsed: elapsed}
+
+	if s.bbbvCells == nil {
+		layout, err := LayoutOf(s.Minefield)
+		if err != nil {
+			// mines are not placed yet, nothing was dug
+			return stats
+		}
+		f := newEmptyMinefield(layout.Width, layout.Height, layout.Topology, layout.Mines.Len())
+		f.placeMines(layout.Mines)
+		for _, index := range f.bbbvCells() {
+			s.bbbvCells = append(s.bbbvCells, f.coordinates(index))
+		}
+	}
+
+	stats.BBBV = len(s.bbbvCells)
+	for _, coord := range s.bbbvCells {
+		if status := s.Minefield.CellStatus(coord.Row, coord.Col); status >= MinesAround1 && status <= NoMinesAround {
+			stats.SolvedBBBV++
+		}
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		stats.BBBVPerSecond = float64(stats.SolvedBBBV) / seconds
+	}
+	if total := s.clicks.Total(); total > 0 {
+		stats.IOE = float64(stats.SolvedBBBV) / float64(total)
+	}
+	if stats.BBBVPerSecond > 0 {
+		stats.RQP = elapsed.Seconds() / stats.BBBVPerSecond
+	}
+	return stats
+}
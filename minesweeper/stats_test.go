@@ -0,0 +1,106 @@
+package minesweeper
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStatsTracker(t *testing.T) {
+	// the flood of (0, 0) is one 3BV click, the isolated numbers at (2, 5) and (3, 5) are the other two
+	s := NewStatsTracker(newChangesTestMinefield(t), Clicks{})
+	steps := []struct {
+		name   string
+		move   func() (ChangeSet, error)
+		clicks Clicks
+		solved int
+	}{
+		{"flood", func() (ChangeSet, error) { return s.Dig(0, 0) }, Clicks{Left: 1}, 1},
+		{"dig a dug cell", func() (ChangeSet, error) { return s.Dig(0, 0) }, Clicks{Left: 2, Wasted: 1}, 1},
+		{"flag", func() (ChangeSet, error) { return s.Flag(0, 5) }, Clicks{Left: 2, Right: 1, Wasted: 1}, 1},
+		{"unflag", func() (ChangeSet, error) { return s.ToggleFlag(0, 5) }, Clicks{Left: 2, Right: 2, Wasted: 1}, 1},
+		{"flag a dug cell", func() (ChangeSet, error) { return s.Flag(2, 2) }, Clicks{Left: 2, Right: 3, Wasted: 2}, 1},
+		{"chord without flags", func() (ChangeSet, error) { return s.Chord(2, 4) },
+			Clicks{Left: 2, Right: 3, Chord: 1, Wasted: 3}, 1},
+		{"flag the mine", func() (ChangeSet, error) { return s.Flag(1, 5) },
+			Clicks{Left: 2, Right: 4, Chord: 1, Wasted: 3}, 1},
+		{"dig an isolated number", func() (ChangeSet, error) { return s.Dig(2, 5) },
+			Clicks{Left: 3, Right: 4, Chord: 1, Wasted: 3}, 2},
+		// undo is not a click, but the undugged number is not solved anymore
+		{"undo", func() (ChangeSet, error) { return s.Undo() }, Clicks{Left: 3, Right: 4, Chord: 1, Wasted: 3}, 1},
+		{"chord the isolated numbers", func() (ChangeSet, error) { return s.Chord(2, 4) },
+			Clicks{Left: 3, Right: 4, Chord: 2, Wasted: 3}, 3},
+		// clicks after the game is over are not counted
+		{"dig after winning", func() (ChangeSet, error) { return s.Dig(0, 0) },
+			Clicks{Left: 3, Right: 4, Chord: 2, Wasted: 3}, 3},
+	}
+	for _, step := range steps {
+		step.move()
+		stats := s.Stats(time.Second)
+		if stats.Clicks != step.clicks {
+			t.Errorf("%v: clicks are %+v, expected %+v", step.name, stats.Clicks, step.clicks)
+		}
+		if stats.BBBV != 3 || stats.SolvedBBBV != step.solved {
+			t.Errorf("%v: solved %v of %v 3BV, expected %v of 3", step.name, stats.SolvedBBBV, stats.BBBV, step.solved)
+		}
+	}
+	if s.GameStatus() != Won {
+		t.Fatalf("game status is %v at the end of the moves", s.GameStatus())
+	}
+
+	// 9 clicks for 3 3BV, in 2 seconds
+	stats := s.Stats(2 * time.Second)
+	measures := []struct {
+		name             string
+		actual, expected float64
+	}{
+		{"3BV/s", stats.BBBVPerSecond, 1.5},
+		{"IOE", stats.IOE, 3.0 / 9},
+		{"RQP", stats.RQP, 2 / 1.5},
+	}
+	for _, measure := range measures {
+		if math.Abs(measure.actual-measure.expected) > 1e-9 {
+			t.Errorf("%v is %v, expected %v", measure.name, measure.actual, measure.expected)
+		}
+	}
+	if state := s.State(); state.Clicks != stats.Clicks {
+		t.Errorf("saved clicks are %+v, expected %+v", state.Clicks, stats.Clicks)
+	}
+}
+
+func TestStatsTrackerResumed(t *testing.T) {
+	s := NewStatsTracker(newChangesTestMinefield(t), Clicks{Left: 2, Wasted: 1})
+	if _, err := s.Dig(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if clicks := s.Clicks(); clicks != (Clicks{Left: 3, Wasted: 1}) {
+		t.Errorf("clicks are %+v, expected the resumed clicks and the dig", clicks)
+	}
+}
+
+func TestStatsTrackerDeferred(t *testing.T) {
+	f, err := GameGenerator().WithSeed(1).WithFirstClickPolicy(FirstClickSafe).Custom(9, 9, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewStatsTracker(f, Clicks{})
+	if _, err := s.Flag(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	// the mines are not placed before the first dig
+	if stats := s.Stats(time.Second); stats.BBBV != 0 || stats.Right != 1 {
+		t.Errorf("stats are %+v before the first dig", stats)
+	}
+
+	if _, err := s.Dig(4, 4); err != nil {
+		t.Fatal(err)
+	}
+	metrics, err := AnalyzeMinefield(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := s.Stats(time.Second); stats.BBBV != metrics.BBBV || stats.SolvedBBBV == 0 {
+		t.Errorf("solved %v of %v 3BV after the first dig, expected some of %v", stats.SolvedBBBV, stats.BBBV,
+			metrics.BBBV)
+	}
+}
@@ -8,6 +8,18 @@ const (
 	Won
 )
 
+func (s GameStatus) String() string {
+	switch s {
+	case GameOn:
+		return "game on"
+	case Lost:
+		return "lost"
+	case Won:
+		return "won"
+	}
+	return "unknown"
+}
+
 type CellStatus int
 
 const (
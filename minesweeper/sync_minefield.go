@@ -0,0 +1,133 @@
+package minesweeper
+
+import (
+	"sync"
+)
+
+// syncMinefield serializes all calls to a minefield. Moves are applied one at a time, in the order they acquire the lock,
+// and reads never observe a move halfway through.
+type syncMinefield struct {
+	mu sync.RWMutex
+	f  Minefield
+}
+
+// NewSyncMinefield wraps a minefield so it can be shared between goroutines.
+// Listeners are called while the move that triggered them holds the lock, so they must not call the minefield back.
+// The wrapped minefield must not be used directly afterwards.
+func NewSyncMinefield(f Minefield) Minefield {
+	return &syncMinefield{f: f}
+}
+
+func (s *syncMinefield) Flag(row, col int) (ChangeSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Flag(row, col)
+}
+
+func (s *syncMinefield) Unflag(row, col int) (ChangeSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Unflag(row, col)
+}
+
+func (s *syncMinefield) ToggleFlag(row, col int) (ChangeSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.ToggleFlag(row, col)
+}
+
+func (s *syncMinefield) Dig(row, col int) (ChangeSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Dig(row, col)
+}
+
+func (s *syncMinefield) Chord(row, col int) (ChangeSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Chord(row, col)
+}
+
+func (s *syncMinefield) Undo() (ChangeSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Undo()
+}
+
+func (s *syncMinefield) Redo() (ChangeSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Redo()
+}
+
+func (s *syncMinefield) CanUndo() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.CanUndo()
+}
+
+func (s *syncMinefield) CanRedo() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.CanRedo()
+}
+
+func (s *syncMinefield) Subscribe(listener Listener) (unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unsubscribeFunc := s.f.Subscribe(listener)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		unsubscribeFunc()
+	}
+}
+
+func (s *syncMinefield) GameStatus() GameStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.GameStatus()
+}
+
+func (s *syncMinefield) CellStatus(row, col int) CellStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.CellStatus(row, col)
+}
+
+// AllCellStatus returns a consistent snapshot, taken between moves
+func (s *syncMinefield) AllCellStatus() [][]CellStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.AllCellStatus()
+}
+
+func (s *syncMinefield) FlagsLeft() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.FlagsLeft()
+}
+
+func (s *syncMinefield) Width() int {
+	return s.f.Width()
+}
+
+func (s *syncMinefield) Height() int {
+	return s.f.Height()
+}
+
+func (s *syncMinefield) Topology() Topology {
+	return s.f.Topology()
+}
+
+func (s *syncMinefield) State() GameState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.State()
+}
+
+func (s *syncMinefield) Seed() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.Seed()
+}
@@ -0,0 +1,140 @@
+package minesweeper
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+const (
+	syncTestGoroutines = 16
+	syncTestMoves      = 200
+)
+
+func newSyncTestMinefield(t *testing.T) Minefield {
+	t.Helper()
+	mines := NewMineList(30, 16, 99)
+	mines.RandomizeWith(rand.New(rand.NewSource(1)), 99)
+	return NewSyncMinefield(NewMinefield(30, 16, mines))
+}
+
+// playRandomly makes random moves, undoing the moves that end the game so the others keep playing
+func playRandomly(f Minefield, seed int64) {
+	rnd := rand.New(rand.NewSource(seed))
+	for i := 0; i < syncTestMoves; i++ {
+		row, col := rnd.Intn(f.Height()), rnd.Intn(f.Width())
+		switch rnd.Intn(4) {
+		case 0:
+			f.Dig(row, col)
+		case 1:
+			f.ToggleFlag(row, col)
+		case 2:
+			f.AllCellStatus()
+		case 3:
+			f.Undo()
+		}
+		if f.GameStatus() != GameOn {
+			f.Undo()
+		}
+	}
+}
+
+// checkConsistent verifies a snapshot matches the flags count, which a move seen halfway through would break
+func checkConsistent(t *testing.T, f Minefield) {
+	t.Helper()
+	flagged := 0
+	for _, row := range f.AllCellStatus() {
+		for _, status := range row {
+			if status == Flagged || status == FlaggedWrong {
+				flagged++
+			}
+		}
+	}
+	if f.GameStatus() == GameOn && flagged != 99-f.FlagsLeft() {
+		t.Errorf("%v cells flagged, expected %v", flagged, 99-f.FlagsLeft())
+	}
+}
+
+// togglable returns a cell whose flag can be toggled, a flagged cell once the flags ran out
+func togglable(t *testing.T, f Minefield) Coordinates {
+	t.Helper()
+	wanted := Undugged
+	if f.FlagsLeft() == 0 {
+		wanted = Flagged
+	}
+	for row, statuses := range f.AllCellStatus() {
+		for col, status := range statuses {
+			if status == wanted {
+				return Coordinates{Row: row, Col: col}
+			}
+		}
+	}
+	t.Fatal("no cell to toggle")
+	return Coordinates{}
+}
+
+func TestSyncMinefieldConcurrentMoves(t *testing.T) {
+	f := newSyncTestMinefield(t)
+
+	var wg sync.WaitGroup
+	for g := 0; g < syncTestGoroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			playRandomly(f, seed)
+		}(int64(g))
+	}
+	wg.Wait()
+
+	checkConsistent(t, f)
+}
+
+func TestSyncMinefieldConcurrentSubscriptions(t *testing.T) {
+	f := newSyncTestMinefield(t)
+
+	var (
+		mu     sync.Mutex
+		events int
+	)
+	listener := func(Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events++
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < syncTestGoroutines; g++ {
+		wg.Add(2)
+		go func(seed int64) {
+			defer wg.Done()
+			playRandomly(f, seed)
+		}(int64(g))
+		go func() {
+			defer wg.Done()
+			for i := 0; i < syncTestMoves; i++ {
+				unsubscribe := f.Subscribe(listener)
+				unsubscribe()
+			}
+		}()
+	}
+	wg.Wait()
+
+	checkConsistent(t, f)
+
+	// a listener left subscribed after the others still gets every event
+	mu.Lock()
+	events = 0
+	mu.Unlock()
+	unsubscribe := f.Subscribe(listener)
+	defer unsubscribe()
+	cell := togglable(t, f)
+	changes, err := f.ToggleFlag(cell.Row, cell.Col)
+	if err != nil {
+		t.Fatalf("toggling a flag at (%v, %v): %v", cell.Row, cell.Col, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if events != len(changes) {
+		t.Errorf("%v events for %v changed cells", events, len(changes))
+	}
+}
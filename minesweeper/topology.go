@@ -0,0 +1,108 @@
+package minesweeper
+
+import "slices"
+
+// Topology - how the edges of a minefield connect
+type Topology int
+
+const (
+	// FlatTopology - the classic board, cells on the edges have fewer neighbours
+	FlatTopology Topology = iota
+	// TorusTopology - the edges wrap around, so the cells of an edge neighbour the cells of the opposite edge
+	TorusTopology
+	// HexTopology - hexagonal cells with six neighbours. The rows are offset, odd rows are shifted half a cell to the
+	// right, so a cell touches the two cells to its sides and two cells in each of the rows above and below it.
+	HexTopology
+)
+
+func (t Topology) String() string {
+	switch t {
+	case FlatTopology:
+		return "flat"
+	case TorusTopology:
+		return "torus"
+	case HexTopology:
+		return "hex"
+	}
+	return "unknown"
+}
+
+func (t Topology) isValid() bool {
+	return t == FlatTopology || t == TorusTopology || t == HexTopology
+}
+
+// SurroundingCells returns the cells around a cell of a width x height minefield.
+// On a torus every cell has eight neighbours, unless the board is too narrow for them to be different cells.
+// On a hex grid cells have up to six neighbours.
+func (t Topology) SurroundingCells(width, height int, coord Coordinates) []Coordinates {
+	var buf [8]int
+	neighbors := t.neighbors(width, height, coord.Row*width+coord.Col, &buf)
+	res := make([]Coordinates, len(neighbors))
+	for i, index := range neighbors {
+		res[i] = indexToCoordinates(index, width)
+	}
+	return res
+}
+
+// neighbors fills buf with the indexes of the cells around a cell, row by row, and returns the filled part of it.
+// It allocates nothing, so it can be used on every cell of a huge board.
+func (t Topology) neighbors(width, height, index int, buf *[8]int) []int {
+	row, col := index/width, index%width
+	n := 0
+	for r := row - 1; r <= row+1; r++ {
+		for c := col - 1; c <= col+1; c++ {
+			if r == row && c == col || t == HexTopology && r != row && c-col == hexFarColumn(row) {
+				continue
+			}
+
+			neighborRow, neighborCol := r, c
+			if t == TorusTopology {
+				neighborRow, neighborCol = (r+height)%height, (c+width)%width
+			} else if r < 0 || r >= height || c < 0 || c >= width {
+				continue
+			}
+
+			neighbor := neighborRow*width + neighborCol
+			if t == TorusTopology && (neighbor == index || slices.Contains(buf[:n], neighbor)) {
+				// a board narrower than three cells wraps onto the same cells
+				continue
+			}
+			buf[n] = neighbor
+			n++
+		}
+	}
+	return buf[:n]
+}
+
+// AreNeighbors reports whether two different cells of a width x height minefield touch each other
+func (t Topology) AreNeighbors(width, height int, a, b Coordinates) bool {
+	if a == b {
+		return false
+	}
+
+	rowDistance, colDistance := abs(a.Row-b.Row), abs(a.Col-b.Col)
+	if t == HexTopology && rowDistance == 1 && b.Col-a.Col == hexFarColumn(a.Row) {
+		return false
+	}
+	if t == TorusTopology {
+		rowDistance, colDistance = min(rowDistance, height-rowDistance), min(colDistance, width-colDistance)
+	}
+	return rowDistance <= 1 && colDistance <= 1
+}
+
+// hexFarColumn returns the column offset, relative to a cell of the given row, of the cells in the rows above and below
+// that are diagonal to it but don't touch it. Even rows touch the cells to the left of the ones above and below them,
+// and odd rows, being shifted to the right, touch the cells to the right.
+func hexFarColumn(row int) int {
+	if row%2 == 0 {
+		return 1
+	}
+	return -1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,104 @@
+package minesweeper
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type neighborsTest struct {
+	name     string
+	cell     Coordinates
+	expected []Coordinates
+}
+
+// checkNeighbors verifies the surrounding cells of each test cell, and that AreNeighbors agrees with them on every
+// cell of the board
+func checkNeighbors(t *testing.T, topology Topology, width, height int, tests []neighborsTest) {
+	t.Helper()
+	for _, test := range tests {
+		cells := topology.SurroundingCells(width, height, test.cell)
+		if !reflect.DeepEqual(cells, test.expected) {
+			t.Errorf("%v: cells around %v are %v, expected %v", test.name, test.cell, cells, test.expected)
+		}
+
+		for index := 0; index < width*height; index++ {
+			other := indexToCoordinates(index, width)
+			expected := slices.Contains(test.expected, other)
+			if topology.AreNeighbors(width, height, test.cell, other) != expected {
+				t.Errorf("%v: %v and %v are neighbors is not %v", test.name, test.cell, other, expected)
+			}
+			if topology.AreNeighbors(width, height, other, test.cell) != expected {
+				t.Errorf("%v: %v and %v are neighbors is not %v", test.name, other, test.cell, expected)
+			}
+		}
+	}
+}
+
+func TestTorusNeighbors(t *testing.T) {
+	checkNeighbors(t, TorusTopology, 5, 4, []neighborsTest{
+		{"top left corner", Coordinates{0, 0},
+			[]Coordinates{{3, 4}, {3, 0}, {3, 1}, {0, 4}, {0, 1}, {1, 4}, {1, 0}, {1, 1}}},
+		{"bottom right corner", Coordinates{3, 4},
+			[]Coordinates{{2, 3}, {2, 4}, {2, 0}, {3, 3}, {3, 0}, {0, 3}, {0, 4}, {0, 0}}},
+		{"top edge", Coordinates{0, 2},
+			[]Coordinates{{3, 1}, {3, 2}, {3, 3}, {0, 1}, {0, 3}, {1, 1}, {1, 2}, {1, 3}}},
+		{"left edge", Coordinates{2, 0},
+			[]Coordinates{{1, 4}, {1, 0}, {1, 1}, {2, 4}, {2, 1}, {3, 4}, {3, 0}, {3, 1}}},
+	})
+
+	// a board narrower than three cells wraps onto the same cells, each is counted once
+	checkNeighbors(t, TorusTopology, 2, 2, []neighborsTest{
+		{"narrow", Coordinates{0, 0}, []Coordinates{{1, 1}, {1, 0}, {0, 1}}},
+	})
+}
+
+func TestTorusFlood(t *testing.T) {
+	tests := []struct {
+		name  string
+		mines []Coordinates
+		// far is a cell past the mines, reached only through the edge
+		far Coordinates
+	}{
+		{"across the left edge", []Coordinates{{0, 2}, {1, 2}, {2, 2}, {3, 2}}, Coordinates{0, 4}},
+		{"across the top edge", []Coordinates{{2, 0}, {2, 1}, {2, 2}, {2, 3}, {2, 4}}, Coordinates{3, 0}},
+	}
+	for _, test := range tests {
+		for _, topology := range []Topology{FlatTopology, TorusTopology} {
+			t.Run(test.name+"/"+topology.String(), func(t *testing.T) {
+				mines := NewMineList(5, 4, len(test.mines))
+				for _, coord := range test.mines {
+					mines.Add(coord.Row, coord.Col)
+				}
+				f := newEmptyMinefield(5, 4, topology, mines.Len())
+				f.placeMines(mines)
+
+				if _, err := f.Dig(0, 0); err != nil {
+					t.Fatal(err)
+				}
+				reached := f.CellStatus(test.far.Row, test.far.Col) != Undugged
+				if reached != (topology == TorusTopology) {
+					t.Errorf("the flood reached %v is %v", test.far, reached)
+				}
+				if topology == TorusTopology && f.GameStatus() != Won {
+					t.Errorf("game status is %v, the flood should reveal every safe cell", f.GameStatus())
+				}
+			})
+		}
+	}
+}
+
+func TestHexNeighbors(t *testing.T) {
+	// odd rows are shifted right, so even rows touch the cells below-left and odd rows the cells below-right
+	checkNeighbors(t, HexTopology, 5, 4, []neighborsTest{
+		{"top left corner", Coordinates{0, 0}, []Coordinates{{0, 1}, {1, 0}}},
+		{"top right corner", Coordinates{0, 4}, []Coordinates{{0, 3}, {1, 3}, {1, 4}}},
+		{"bottom right corner", Coordinates{3, 4}, []Coordinates{{2, 4}, {3, 3}}},
+		{"bottom left corner", Coordinates{3, 0}, []Coordinates{{2, 0}, {2, 1}, {3, 1}}},
+		{"odd row left edge", Coordinates{1, 0}, []Coordinates{{0, 0}, {0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+		{"odd row right edge", Coordinates{1, 4}, []Coordinates{{0, 4}, {1, 3}, {2, 4}}},
+		{"even row left edge", Coordinates{2, 0}, []Coordinates{{1, 0}, {2, 1}, {3, 0}}},
+		{"even row inside", Coordinates{2, 2}, []Coordinates{{1, 1}, {1, 2}, {2, 1}, {2, 3}, {3, 1}, {3, 2}}},
+		{"odd row inside", Coordinates{1, 2}, []Coordinates{{0, 2}, {0, 3}, {1, 1}, {1, 3}, {2, 2}, {2, 3}}},
+	})
+}